@@ -0,0 +1,159 @@
+package gowebview
+
+import (
+	"sync"
+)
+
+// App manages multiple windows, which runs on the same UI thread and shares the same browser environment and Profile.
+type App struct {
+	config *Config
+	app    *application
+
+	mutex              sync.Mutex
+	windows            map[*appWindow]struct{}
+	opened             int
+	onLastWindowClosed []func()
+
+	done chan struct{}
+	once sync.Once
+}
+
+// NewApp creates the App. The given Config defines the Profile, the LibraryDir, the UserDataDir, the TransportConfig
+// and the browser runtime, which are shared by all windows. The Config might be nil.
+func NewApp(config *Config) (*App, error) {
+	config, err := setDefaults(config)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := newApplication(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &App{
+		config:  config,
+		app:     app,
+		windows: make(map[*appWindow]struct{}),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// NewWindow creates a new window. The Profile, LibraryDir, UserDataDir, Ephemeral, TransportConfig and the browser
+// runtime are inherited from the App, those values are ignored if defined on the given Config. It is safe to call this
+// function from a background thread.
+func (a *App) NewWindow(config *Config) (WebView, error) {
+	if config == nil {
+		config = new(Config)
+	}
+
+	config.Profile = a.config.Profile
+	config.UserDataDir = a.config.UserDataDir
+	config.LibraryDir = a.config.LibraryDir
+	config.Ephemeral = false
+	config.TransportConfig = a.config.TransportConfig
+	config.BrowserExecutableDir = a.config.BrowserExecutableDir
+	config.ReleaseChannel = a.config.ReleaseChannel
+	config.MinimumRuntimeVersion = a.config.MinimumRuntimeVersion
+
+	config, err := setDefaults(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// The window is registered before it's created, since it might be closed before newWindow returns.
+	window := new(appWindow)
+
+	a.mutex.Lock()
+	a.windows[window] = struct{}{}
+	a.opened++
+	a.mutex.Unlock()
+
+	w, err := a.app.newWindow(config, func() {
+		a.mutex.Lock()
+		delete(a.windows, window)
+		a.opened--
+		last := a.opened == 0
+		listeners := a.onLastWindowClosed
+		a.mutex.Unlock()
+
+		if last {
+			a.lastWindowClosed(listeners)
+		}
+	})
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err != nil {
+		delete(a.windows, window)
+		a.opened--
+		return nil, err
+	}
+
+	window.view = w
+	return w, nil
+}
+
+// appWindow is the window of the App, the view is nil until the window is created.
+type appWindow struct {
+	view WebView
+}
+
+// NewTray adds the icon into the notification area of the taskbar. The Tray runs on the UI thread of the App, and it's
+// removed when the App is terminated. The hidden windows aren't closed, so the App keeps running while they exist. It
+// returns ErrFeatureNotSupported on Android. The config might be nil.
+func (a *App) NewTray(config *TrayConfig) (Tray, error) {
+	if config == nil {
+		config = new(TrayConfig)
+	}
+
+	return a.app.newTray(config)
+}
+
+// OnLastWindowClosed adds a function which is called when the last window is closed. If none is defined, the App is
+// terminated when the last window is closed. The function is called from the UI thread.
+func (a *App) OnLastWindowClosed(f func()) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.onLastWindowClosed = append(a.onLastWindowClosed, f)
+}
+
+func (a *App) lastWindowClosed(listeners []func()) {
+	if len(listeners) == 0 {
+		a.Terminate()
+		return
+	}
+
+	for _, f := range listeners {
+		f()
+	}
+}
+
+// Run runs the App until it's terminated.
+func (a *App) Run() {
+	<-a.done
+}
+
+// Terminate destroys all windows and stops the App. With Config.Ephemeral, it also waits until the profile is wiped,
+// unless it's called from the UI thread. It is safe to call this function from a background thread.
+func (a *App) Terminate() {
+	a.once.Do(func() {
+		a.mutex.Lock()
+		windows := make([]WebView, 0, len(a.windows))
+		for w := range a.windows {
+			if w.view != nil {
+				windows = append(windows, w.view)
+			}
+		}
+		a.mutex.Unlock()
+
+		for _, w := range windows {
+			w.Destroy()
+		}
+
+		a.app.terminate()
+		close(a.done)
+	})
+}
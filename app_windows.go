@@ -0,0 +1,454 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/inkeliz/gowebview/internal/dispatch"
+	"github.com/inkeliz/gowebview/internal/network"
+	"github.com/inkeliz/gowebview/internal/version"
+	"github.com/inkeliz/gowebview/internal/wincom"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32 = windows.NewLazySystemDLL("user32.dll")
+
+	threadlist sync.Map
+
+	wakeClassOnce sync.Once
+	wakeClassErr  error
+)
+
+// wakeMessage is posted to the wake window when a function is dispatched.
+const wakeMessage = w32.WM_APP + 2
+
+type application struct {
+	thread *thread
+}
+
+func newApplication(config *Config) (*application, error) {
+	t, err := newThread(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &application{thread: t}, nil
+}
+
+func (a *application) newWindow(config *Config, closed func()) (WebView, error) {
+	w, err := a.thread.newWebview(config, closed)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (a *application) newTray(config *TrayConfig) (Tray, error) {
+	return a.thread.newTray(config)
+}
+
+func (a *application) terminate() {
+	a.thread.quit()
+	a.thread.wait()
+}
+
+// thread is the UI thread, which runs the message loop. All windows of the same thread shares the same browser
+// environment.
+type thread struct {
+	id    uint32
+	queue *dispatch.Queue
+
+	// wake is the message-only window which drains the queue. The messages posted to a window are also dispatched by
+	// the modal loops, such as of the MessageBox or while resizing, unlike the messages posted to the thread.
+	wake w32.HWND
+
+	config *Config
+	dll    *windows.Proc
+
+	environment *wincom.ICoreWebView2Environment
+	waiting     []func(environment *wincom.ICoreWebView2Environment, err error)
+
+	taskbar *wincom.ITaskbarList3
+
+	views map[*webview]bool
+	trays map[*tray]bool
+
+	// release releases the Profile, which is used by the thread until it ends. The ephemeral profile is only wiped
+	// after that, since the browser environment keeps using it. The done is closed once the thread ends.
+	release func()
+	done    chan struct{}
+}
+
+func newThread(config *Config) (*thread, error) {
+	t := &thread{config: config, views: make(map[*webview]bool), trays: make(map[*tray]bool), done: make(chan struct{})}
+	t.queue = dispatch.New(func() {
+		// Wakes up the GetMessage, otherwise the function only runs when the window receives some message.
+		w32.PostMessage(t.wake, wakeMessage, 0, 0)
+	}, t.current)
+
+	// The host of an embedded WebView owns the DPI awareness of the process, which can't be changed after it's set.
+	if config.WindowConfig.Window == 0 {
+		setDpiAwareness()
+	}
+
+	if err := extract(config.LibraryDir); err != nil {
+		return nil, &kindError{kind: ErrLibraryExtraction, err: err}
+	}
+
+	if config.TransportConfig.IgnoreNetworkIsolation && !network.IsAllowedPrivateConnections() {
+		if err := network.EnablePrivateConnections(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range []string{"WEBVIEW2_BROWSER_EXECUTABLE_FOLDER", "WEBVIEW2_USER_DATA_FOLDER", "WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS", "WEBVIEW2_RELEASE_CHANNEL_PREFERENCE"} {
+		os.Unsetenv(s)
+	}
+
+	t.setProxy(config.TransportConfig.Proxy)
+	t.setCerts(config.TransportConfig.CertificateAuthorities)
+
+	if config.ReleaseChannel == ReleaseChannelCanary {
+		os.Setenv("WEBVIEW2_RELEASE_CHANNEL_PREFERENCE", "1")
+	}
+
+	if config.BrowserExecutableDir != "" {
+		if err := validateBrowserExecutableDir(config.BrowserExecutableDir); err != nil {
+			return nil, err
+		}
+	}
+
+	dll, err := windows.LoadDLL(filepath.Join(config.LibraryDir, "WebView2Loader.dll"))
+	if err != nil {
+		return nil, &kindError{kind: ErrLibraryExtraction, err: err}
+	}
+
+	t.dll, err = dll.FindProc("CreateCoreWebView2EnvironmentWithOptions")
+	if err != nil {
+		return nil, &kindError{kind: ErrLibraryExtraction, err: err}
+	}
+
+	v, err := availableVersion(dll, config.BrowserExecutableDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := version.Check(v, config.MinimumRuntimeVersion); err != nil {
+		return nil, err
+	}
+
+	if t.release, err = config.Profile.acquire(); err != nil {
+		return nil, err
+	}
+
+	started := make(chan error)
+	go func() {
+		runtime.LockOSThread()
+		w32.CoInitializeEx(w32.COINIT_APARTMENTTHREADED)
+
+		t.id = windows.GetCurrentThreadId()
+		if err := t.createWake(); err != nil {
+			t.queue.Close()
+			w32.CoUninitialize()
+			t.release()
+			started <- err
+			return
+		}
+		close(started)
+
+		err := t.loop()
+		if err == nil {
+			err = ErrTerminated
+		}
+
+		// The windows can't be used without the message loop.
+		views := make([]*webview, 0, len(t.views))
+		for w := range t.views {
+			views = append(views, w)
+			w.destroy()
+		}
+
+		for tr := range t.trays {
+			tr.remove()
+		}
+
+		if t.taskbar != nil {
+			syscall.Syscall(t.taskbar.VTBL.Release, 1, uintptr(unsafe.Pointer(t.taskbar)), 0, 0)
+		}
+
+		if t.environment != nil {
+			syscall.Syscall(t.environment.VTBL.Release, 1, uintptr(unsafe.Pointer(t.environment)), 0, 0)
+			t.environment = nil
+		}
+
+		t.queue.Close()
+
+		threadlist.Delete(t.wake)
+		w32.DestroyWindow(t.wake)
+
+		// The COM objects must be released before the CoUninitialize, and the windows are only finished after that,
+		// so Destroy returns once the thread is clean.
+		w32.CoUninitialize()
+		t.release()
+		for _, w := range views {
+			w.finish(err)
+		}
+		close(t.done)
+	}()
+
+	if err := <-started; err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// createWake creates the message-only window which receives the wakeMessage. It must be called from the UI thread.
+func (t *thread) createWake() error {
+	instance := w32.GetModuleHandle("")
+
+	wakeClassOnce.Do(func() {
+		if _, ok := w32.GetClassInfoEx(instance, "webview-wake"); ok {
+			return
+		}
+
+		class := w32.RegisterClassEx(&w32.WNDCLASSEX{
+			WndProc:   windows.NewCallback(watchWake),
+			Instance:  instance,
+			ClassName: windows.StringToUTF16Ptr("webview-wake"),
+		})
+		if class == 0 {
+			wakeClassErr = errors.New("RegisterClassEx fails")
+		}
+	})
+	if wakeClassErr != nil {
+		return wakeClassErr
+	}
+
+	t.wake = w32.CreateWindowEx(0, windows.StringToUTF16Ptr("webview-wake"), windows.StringToUTF16Ptr(""), 0, 0, 0, 0, 0, w32.HWND_MESSAGE, 0, instance, nil)
+	if t.wake == 0 {
+		return errors.New("CreateWindowEx failed")
+	}
+
+	threadlist.Store(t.wake, t)
+	return nil
+}
+
+func watchWake(hwnd w32.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	tt, ok := threadlist.Load(hwnd)
+	if !ok || msg != wakeMessage {
+		return w32.DefWindowProc(hwnd, msg, wParam, lParam)
+	}
+
+	tt.(*thread).queue.Drain()
+	return 0
+}
+
+func (t *thread) setProxy(proxy *HTTPProxy) {
+	if proxy == nil || (proxy.IP == "" && proxy.Port == "") {
+		return
+	}
+
+	t.addEnv(` --proxy-server="%s"`, proxy.String())
+}
+
+func (t *thread) setCerts(certs []x509.Certificate) {
+	if certs == nil || len(certs) == 0 {
+		return
+	}
+
+	var jcerts string
+	h := sha256.New()
+	for _, c := range certs {
+		h.Write(c.RawSubjectPublicKeyInfo)
+		jcerts += base64.StdEncoding.EncodeToString(h.Sum(nil)) + ","
+		h.Reset()
+	}
+
+	t.addEnv(` --ignore-certificate-errors-spki-list="%s"`, jcerts)
+}
+
+func (t *thread) addEnv(argument, value string) {
+	os.Setenv("WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS", os.Getenv("WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS")+" "+fmt.Sprintf(argument, value))
+}
+
+// current returns true if it's called from the UI thread.
+func (t *thread) current() bool {
+	return windows.GetCurrentThreadId() == t.id
+}
+
+// dispatch runs the function on the UI thread. It is safe to call this function from a background thread. It returns
+// false if the thread has ended, then the function never runs.
+func (t *thread) dispatch(f func()) bool {
+	return t.queue.Dispatch(f)
+}
+
+// quit stops the message loop.
+func (t *thread) quit() {
+	t.dispatch(func() {
+		w32.PostQuitMessage(0)
+	})
+}
+
+// wait blocks until the thread ends and the ephemeral profile is wiped. It does nothing on the UI thread, which can't
+// wait for itself.
+func (t *thread) wait() {
+	if t.current() {
+		return
+	}
+
+	<-t.done
+	t.config.Profile.wait()
+}
+
+// loop runs the message loop, the queue is drained by the wake window.
+func (t *thread) loop() (err error) {
+	msg := new(w32.MSG)
+	for {
+		switch w32.GetMessage(msg, 0, 0, 0) {
+		case -1:
+			return errors.New("GetMessage fails")
+		case 0:
+			return nil
+		}
+
+		w32.TranslateMessage(msg)
+		w32.DispatchMessage(msg)
+	}
+}
+
+// withEnvironment calls the function with the browser environment, which is created by the first window. It must be
+// called from the UI thread.
+func (t *thread) withEnvironment(f func(environment *wincom.ICoreWebView2Environment, err error)) {
+	if t.environment != nil {
+		f(t.environment, nil)
+		return
+	}
+
+	t.waiting = append(t.waiting, f)
+	if len(t.waiting) > 1 {
+		return
+	}
+
+	h := &environmentHandler{thread: t}
+	h.VTBL = environmentHandlerVTBL
+	pending.Store(h, true)
+
+	// The browserExecutableFolder is NULL to use the installed runtime.
+	var folder uintptr
+	if t.config.BrowserExecutableDir != "" {
+		folder = uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(t.config.BrowserExecutableDir)))
+	}
+
+	res, _, _ := t.dll.Call(folder, uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(t.config.Profile.Path()))), 0, uintptr(unsafe.Pointer(h)))
+	if res != 0 {
+		pending.Delete(h)
+		t.environmentCompleted(nil, environmentError(res))
+	}
+}
+
+func (t *thread) environmentCompleted(environment *wincom.ICoreWebView2Environment, err error) {
+	if err == nil {
+		syscall.Syscall(environment.VTBL.AddRef, 1, uintptr(unsafe.Pointer(environment)), 0, 0)
+		t.environment = environment
+	}
+
+	waiting := t.waiting
+	t.waiting = nil
+
+	for _, f := range waiting {
+		f(environment, err)
+	}
+}
+
+// resetEnvironment releases the environment after the browser process exits, so the next browser creates a new one.
+// It does nothing if the environment was already replaced, since all windows are notified about the same failure.
+func (t *thread) resetEnvironment(environment *wincom.ICoreWebView2Environment) {
+	if environment == nil || t.environment != environment {
+		return
+	}
+
+	syscall.Syscall(t.environment.VTBL.Release, 1, uintptr(unsafe.Pointer(t.environment)), 0, 0)
+	t.environment = nil
+}
+
+// environmentError returns the error of CreateCoreWebView2EnvironmentWithOptions, which returns
+// HRESULT_FROM_WIN32(ERROR_FILE_NOT_FOUND) when the WebView2 runtime isn't installed.
+func environmentError(code uintptr) error {
+	err := hresult("CreateCoreWebView2EnvironmentWithOptions", code)
+	if code == uintptr(0x80070002) {
+		return &kindError{kind: ErrRuntimeNotInstalled, err: err}
+	}
+	return err
+}
+
+// environmentHandler implements ICoreWebView2CreateCoreWebView2EnvironmentCompletedHandler.
+type environmentHandler struct {
+	wincom.ICoreWebView2CreateCoreWebView2EnvironmentCompletedHandler
+	thread *thread
+}
+
+var environmentHandlerVTBL = &wincom.ICoreWebView2CreateCoreWebView2EnvironmentCompletedHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *environmentHandler, errorCode uintptr, createdEnvironment *wincom.ICoreWebView2Environment) uintptr {
+		pending.Delete(h)
+
+		if errorCode != 0 {
+			h.thread.environmentCompleted(nil, environmentError(errorCode))
+			return 0
+		}
+
+		h.thread.environmentCompleted(createdEnvironment, nil)
+		return 0
+	}),
+}
+
+// controllerHandler implements ICoreWebView2CreateCoreWebView2ControllerCompletedHandler.
+type controllerHandler struct {
+	wincom.ICoreWebView2CreateCoreWebView2ControllerCompletedHandler
+	webview     *webview
+	environment *wincom.ICoreWebView2Environment
+	completed   func(err error)
+}
+
+// controllerHandlerVTBL is initialized by init, since the callback might recreate the browser, which refers to the
+// VTBL itself.
+var controllerHandlerVTBL = new(wincom.ICoreWebView2CreateCoreWebView2ControllerCompletedHandlerVTBL)
+
+func init() {
+	*controllerHandlerVTBL = wincom.ICoreWebView2CreateCoreWebView2ControllerCompletedHandlerVTBL{
+		BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+		Invoke: windows.NewCallback(func(h *controllerHandler, errorCode uintptr, createdController *wincom.ICoreWebView2Controller) uintptr {
+			pending.Delete(h)
+
+			if errorCode != 0 {
+				h.completed(hresult("CreateCoreWebView2Controller", errorCode))
+				return 0
+			}
+
+			// The window might be closed while the browser is created.
+			if h.webview.view.window == 0 {
+				syscall.Syscall(createdController.VTBL.Close, 1, uintptr(unsafe.Pointer(createdController)), 0, 0)
+				h.completed(ErrClosed)
+				return 0
+			}
+
+			h.webview.controllerCompleted(h.environment, createdController)
+			h.completed(nil)
+			return 0
+		}),
+	}
+}
@@ -0,0 +1,79 @@
+package gowebview
+
+import (
+	"image"
+	"net/url"
+	"strings"
+)
+
+// Clipboard reads and writes the clipboard of the operating system. It's safe to use from any goroutine. It's
+// implemented on Windows and Android, the X11 selections of Linux are pending, see the README.
+type Clipboard interface {
+	// ReadText returns the text of the clipboard, or an empty string if it doesn't have text.
+	ReadText() (string, error)
+
+	// WriteText replaces the content of the clipboard with the text.
+	WriteText(text string) error
+
+	// ReadImage returns the image of the clipboard, or nil if it doesn't have an image. It returns
+	// ErrFeatureNotSupported on Android.
+	ReadImage() (image.Image, error)
+
+	// WriteImage replaces the content of the clipboard with the image. It returns ErrFeatureNotSupported on
+	// Android.
+	WriteImage(img image.Image) error
+}
+
+// messageClipboard is posted by the clipboardScript, followed by the text.
+const messageClipboard = "gowebview:clipboard:"
+
+// clipboardScript wraps the navigator.clipboard.writeText of the page, the text is posted to the host when the
+// browser rejects it, such as when the page doesn't have the focus. The fallback keeps the user gesture requirement,
+// and it's only installed on the top-level document of secure pages. Any script of the page can post the message, so
+// the host checks the origin and the recent input of the user again, see clipboardOrigin.
+const clipboardScript = `(function () {
+	if (window !== window.top || !window.isSecureContext || !navigator.clipboard) {
+		return;
+	}
+
+	var writeText = navigator.clipboard.writeText.bind(navigator.clipboard);
+	navigator.clipboard.writeText = function (text) {
+		// The activation is checked before the call, since the rejection is asynchronous.
+		var active = navigator.userActivation && navigator.userActivation.isActive;
+		return writeText(text).catch(function (err) {
+			if (!active) {
+				throw err;
+			}
+			window.chrome.webview.postMessage('` + messageClipboard + `' + String(text));
+		});
+	};
+})();`
+
+// clipboardOrigin returns true if the page, at the given URL, is allowed to write to the clipboard using the
+// clipboardScript. The origin must be the origin of the Config.URL or one of the WindowConfig.ClipboardOrigins.
+func clipboardOrigin(source string, config *Config) bool {
+	origin := urlOrigin(source)
+	if origin == "" {
+		return false
+	}
+
+	if origin == urlOrigin(config.URL) {
+		return true
+	}
+	for _, o := range config.WindowConfig.ClipboardOrigins {
+		if origin == urlOrigin(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// urlOrigin returns the scheme and the host of the URL, in lowercase, or an empty string if the URL doesn't have
+// the scheme or the host.
+func urlOrigin(u string) string {
+	p, err := url.Parse(u)
+	if err != nil || p.Scheme == "" || p.Host == "" {
+		return ""
+	}
+	return strings.ToLower(p.Scheme + "://" + p.Host)
+}
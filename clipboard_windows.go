@@ -0,0 +1,163 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"context"
+	"errors"
+	"github.com/inkeliz/gowebview/internal/dib"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"image"
+	"time"
+	"unsafe"
+)
+
+var (
+	kernel32         = windows.NewLazySystemDLL("kernel32.dll")
+	globalSize       = kernel32.NewProc("GlobalSize")
+	getTickCount     = kernel32.NewProc("GetTickCount")
+	getLastInputInfo = user32.NewProc("GetLastInputInfo")
+)
+
+// userActivation is how long an input of the user allows the clipboard fallback, which is the same duration of the
+// transient activation of the browser.
+const userActivation = 5 * time.Second
+
+// clipboard uses the window as the owner of the clipboard, since the SetClipboardData fails without the owner.
+type clipboard struct {
+	webview *webview
+}
+
+func (w *webview) Clipboard() Clipboard {
+	return &clipboard{webview: w}
+}
+
+func (c *clipboard) ReadText() (text string, err error) {
+	err = c.open(func() error {
+		b := clipboardData(w32.CF_UNICODETEXT)
+		if len(b) < 2 {
+			return nil
+		}
+
+		text = windows.UTF16ToString((*[1 << 29]uint16)(unsafe.Pointer(&b[0]))[: len(b)/2 : len(b)/2])
+		return nil
+	})
+	return text, err
+}
+
+func (c *clipboard) WriteText(text string) error {
+	s, err := windows.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+
+	return c.open(func() error {
+		return setClipboardData(w32.CF_UNICODETEXT, (*[1 << 30]byte)(unsafe.Pointer(&s[0]))[:len(s)*2:len(s)*2])
+	})
+}
+
+func (c *clipboard) ReadImage() (img image.Image, err error) {
+	err = c.open(func() error {
+		b := clipboardData(w32.CF_DIB)
+		if b == nil {
+			return nil
+		}
+
+		img, err = dib.Decode(b)
+		return err
+	})
+	return img, err
+}
+
+func (c *clipboard) WriteImage(img image.Image) error {
+	b := dib.Encode(img)
+	return c.open(func() error {
+		return setClipboardData(w32.CF_DIB, b)
+	})
+}
+
+// open calls the function on the UI thread, while the clipboard is open. Other applications might hold the
+// clipboard for a short time, so it retries for a while.
+func (c *clipboard) open(f func() error) error {
+	w := c.webview
+	return w.thread.queue.DispatchSync(context.Background(), func() error {
+		for i := 0; !w32.OpenClipboard(w.view.window); i++ {
+			if i == 10 {
+				return errors.New("OpenClipboard fails")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		defer w32.CloseClipboard()
+
+		return f()
+	})
+}
+
+// clipboardData copies the data of the format, it returns nil if the clipboard doesn't have it. The clipboard must
+// be open.
+func clipboardData(format uint) []byte {
+	h := w32.HGLOBAL(w32.GetClipboardData(format))
+	if h == 0 {
+		return nil
+	}
+
+	size, _, _ := globalSize.Call(uintptr(h))
+	p := w32.GlobalLock(h)
+	if p == nil || size == 0 {
+		return nil
+	}
+	defer w32.GlobalUnlock(h)
+
+	b := make([]byte, size)
+	copy(b, (*[1 << 30]byte)(p)[:size:size])
+	return b
+}
+
+// setClipboardData replaces the content of the clipboard with the data. The clipboard must be open.
+func setClipboardData(format uint, data []byte) error {
+	h := w32.GlobalAlloc(w32.GMEM_MOVEABLE, uint32(len(data)))
+	if h == 0 {
+		return errors.New("GlobalAlloc fails")
+	}
+
+	p := w32.GlobalLock(h)
+	if p == nil {
+		w32.GlobalFree(h)
+		return errors.New("GlobalLock fails")
+	}
+	copy((*[1 << 30]byte)(p)[:len(data):len(data)], data)
+	w32.GlobalUnlock(h)
+
+	// The system owns the memory after the SetClipboardData succeeds.
+	if !w32.EmptyClipboard() || w32.SetClipboardData(format, w32.HANDLE(h)) == 0 {
+		w32.GlobalFree(h)
+		return errors.New("SetClipboardData fails")
+	}
+	return nil
+}
+
+// userGesture returns true if the user has pressed a key or clicked recently, while the window is in the foreground.
+// The page can post the messageClipboard without the clipboardScript, so the host can't trust the check of the
+// script. It must be called from the UI thread.
+func (w *webview) userGesture() bool {
+	// GA_ROOT is 2, the WebView might be a child window.
+	if root, _, _ := getAncestor.Call(uintptr(w.view.window), 2); w32.GetForegroundWindow() != w32.HWND(root) {
+		return false
+	}
+
+	// LASTINPUTINFO is the size of the struct followed by the tick count of the last input.
+	info := struct{ size, time uint32 }{size: 8}
+	if ok, _, _ := getLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return false
+	}
+
+	now, _, _ := getTickCount.Call()
+	return recentInput(uint32(now), info.time)
+}
+
+// recentInput returns true if the input, at the given tick count, happened within the userActivation. The tick count
+// wraps around every 49.7 days, the subtraction handles it.
+func recentInput(now, input uint32) bool {
+	return time.Duration(now-input)*time.Millisecond <= userActivation
+}
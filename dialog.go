@@ -0,0 +1,55 @@
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/filter"
+)
+
+// FileFilter restricts the files displayed by the file dialogs, such as "Images" with "*.png" and "*.jpg".
+type FileFilter struct {
+	Name     string
+	Patterns []string
+}
+
+// ParseFileFilters parses the filters, such as "Images|*.png;*.jpg|All Files|*.*". The names and the patterns
+// alternate, separated by "|", and the patterns are separated by ";". It returns ErrInvalidFileFilter if the spec is
+// malformed.
+func ParseFileFilters(spec string) ([]FileFilter, error) {
+	filters, err := filter.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]FileFilter, len(filters))
+	for i, f := range filters {
+		r[i] = FileFilter(f)
+	}
+	return r, nil
+}
+
+// FileDialogOptions defines the file dialogs, see WebView.OpenFileDialog and WebView.SaveFileDialog.
+type FileDialogOptions struct {
+	// Title is the title of the dialog. It's ignored on Android.
+	Title string
+
+	// Directory is the initial directory. It's ignored on Android.
+	Directory string
+
+	// Filename is the initial name of the file, used by the SaveFileDialog.
+	Filename string
+
+	// Filters restricts the files displayed, the first one is selected. Android only uses the known extensions,
+	// such as "*.png", otherwise all files are displayed.
+	Filters []FileFilter
+
+	// Multiple allows selecting multiple files, used by the OpenFileDialog.
+	Multiple bool
+}
+
+// filters converts the FileFilter into filter.Filter.
+func (o *FileDialogOptions) filters() []filter.Filter {
+	r := make([]filter.Filter, len(o.Filters))
+	for i, f := range o.Filters {
+		r[i] = filter.Filter(f)
+	}
+	return r
+}
@@ -0,0 +1,141 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"context"
+	"fmt"
+	"github.com/inkeliz/gowebview/internal/filter"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// fileBufferSize is the size of the buffer which receives the selected files, in characters. It must be large enough
+// for the multiple selection, which contains all names.
+const fileBufferSize = 32 * 1024
+
+func (w *webview) OpenFileDialog(options *FileDialogOptions) (files []string, err error) {
+	if options == nil {
+		options = new(FileDialogOptions)
+	}
+
+	err = w.thread.queue.DispatchSync(context.Background(), func() (err error) {
+		files, err = w.fileDialog(options, false)
+		return err
+	})
+	return files, err
+}
+
+func (w *webview) SaveFileDialog(options *FileDialogOptions) (file string, err error) {
+	if options == nil {
+		options = new(FileDialogOptions)
+	}
+
+	err = w.thread.queue.DispatchSync(context.Background(), func() error {
+		files, err := w.fileDialog(options, true)
+		if len(files) > 0 {
+			file = files[0]
+		}
+		return err
+	})
+	return file, err
+}
+
+// fileDialog displays the dialog and blocks until the user closes it, it returns nil if the user cancels it. It must
+// be called from the UI thread.
+func (w *webview) fileDialog(options *FileDialogOptions, save bool) ([]string, error) {
+	buf := make([]uint16, fileBufferSize)
+	if options.Filename != "" {
+		copy(buf[:len(buf)-1], utf16.Encode([]rune(options.Filename)))
+	}
+
+	ofn := w32.OPENFILENAME{
+		Owner:   w.view.window,
+		Filter:  fileFilter(options.filters()),
+		File:    &buf[0],
+		MaxFile: uint32(len(buf)),
+		Flags:   w32.OFN_EXPLORER | w32.OFN_NOCHANGEDIR | w32.OFN_PATHMUSTEXIST,
+	}
+	if options.Title != "" {
+		ofn.Title = windows.StringToUTF16Ptr(options.Title)
+	}
+	if options.Directory != "" {
+		ofn.InitialDir = windows.StringToUTF16Ptr(options.Directory)
+	}
+
+	var ok bool
+	if save {
+		// The extension of the first filter is appended when the user doesn't type one.
+		if len(options.Filters) > 0 && len(options.Filters[0].Patterns) > 0 {
+			if p := options.Filters[0].Patterns[0]; strings.HasPrefix(p, "*.") && !strings.ContainsAny(p[2:], "*?[") {
+				ofn.DefExt = windows.StringToUTF16Ptr(p[2:])
+			}
+		}
+
+		ofn.Flags |= w32.OFN_OVERWRITEPROMPT
+		ok = w32.GetSaveFileName(&ofn)
+	} else {
+		ofn.Flags |= w32.OFN_FILEMUSTEXIST
+		if options.Multiple {
+			ofn.Flags |= w32.OFN_ALLOWMULTISELECT
+		}
+		ok = w32.GetOpenFileName(&ofn)
+	}
+
+	if !ok {
+		// The CommDlgExtendedError is zero if the user cancels the dialog.
+		if code := w32.CommDlgExtendedError(); code != 0 {
+			return nil, fmt.Errorf("file dialog fails: 0x%x", code)
+		}
+		return nil, nil
+	}
+
+	return selectedFiles(buf), nil
+}
+
+// fileFilter encodes the filters as expected by the OPENFILENAME, such as "Images\0*.png;*.jpg\0\0". It returns nil if
+// there is no filter.
+func fileFilter(filters []filter.Filter) *uint16 {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	var s []uint16
+	for _, f := range filters {
+		s = append(s, utf16.Encode([]rune(f.Name))...)
+		s = append(s, 0)
+		s = append(s, utf16.Encode([]rune(strings.Join(f.Patterns, ";")))...)
+		s = append(s, 0)
+	}
+	s = append(s, 0)
+	return &s[0]
+}
+
+// selectedFiles decodes the buffer of the OPENFILENAME. The multiple selection is the directory followed by the names
+// of the files, separated by NUL, otherwise it's the path of the file.
+func selectedFiles(buf []uint16) []string {
+	var parts []string
+	for start, i := 0, 0; i < len(buf); i++ {
+		if buf[i] != 0 {
+			continue
+		}
+		if i == start {
+			break
+		}
+		parts = append(parts, string(utf16.Decode(buf[start:i])))
+		start = i + 1
+	}
+
+	if len(parts) <= 1 {
+		return parts
+	}
+
+	files := make([]string, len(parts)-1)
+	for i, name := range parts[1:] {
+		files[i] = filepath.Join(parts[0], name)
+	}
+	return files
+}
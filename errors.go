@@ -0,0 +1,167 @@
+package gowebview
+
+import (
+	"errors"
+	"fmt"
+	"github.com/inkeliz/gowebview/internal/accel"
+	"github.com/inkeliz/gowebview/internal/dispatch"
+	"github.com/inkeliz/gowebview/internal/filter"
+	"github.com/inkeliz/gowebview/internal/menu"
+	"github.com/inkeliz/gowebview/internal/version"
+	"strings"
+)
+
+var (
+	// ErrFeatureNotSupported is returned when the feature isn't available on the current platform or browser.
+	ErrFeatureNotSupported = errors.New("feature not supported")
+
+	// ErrRuntimeNotInstalled is returned when the browser runtime (such as WebView2) isn't installed.
+	ErrRuntimeNotInstalled = errors.New("browser runtime not installed")
+
+	// ErrRuntimeVersion is returned when the browser runtime is older than the Config.MinimumRuntimeVersion.
+	ErrRuntimeVersion = version.ErrUnsupported
+
+	// ErrInvalidVersion is returned when the Config.MinimumRuntimeVersion or the BootstrapConfig.MinimumVersion
+	// can't be parsed.
+	ErrInvalidVersion = version.ErrInvalid
+
+	// ErrLibraryExtraction is returned when the library (such as WebView2Loader.dll) can't be extracted into the
+	// LibraryDir or can't be loaded from there.
+	ErrLibraryExtraction = errors.New("impossible to extract the library")
+
+	// ErrImpossibleProxy is returned when the HTTPProxy can't be used.
+	ErrImpossibleProxy = errors.New("impossible to set proxy")
+
+	// ErrImpossibleCertificates is returned when the CertificateAuthorities can't be used.
+	ErrImpossibleCertificates = errors.New("impossible to set certs")
+
+	// ErrProfileRemoved is returned when try to use a Profile after Profile.Remove is called.
+	ErrProfileRemoved = errors.New("profile was removed")
+
+	// ErrWindowClosed is returned by RunContext when the window is closed by the user.
+	ErrWindowClosed = errors.New("window closed by the user")
+
+	// ErrTerminated is returned by RunContext when the WebView is stopped by Terminate or Destroy.
+	ErrTerminated = errors.New("terminated")
+
+	// ErrBrowserProcessFailed is returned by RunContext when the browser process exits unexpectedly.
+	ErrBrowserProcessFailed = errors.New("browser process failed")
+
+	// ErrMissingView is returned on Android when the WindowConfig.VM or the WindowConfig.Window is zero.
+	ErrMissingView = errors.New("missing VM or View")
+
+	// ErrClosed is returned when the UI thread is stopped, such as when the WebView is destroyed.
+	ErrClosed = dispatch.ErrClosed
+
+	// ErrInvalidMenu is returned when the MenuItem is inconsistent, such as a separator with a submenu.
+	ErrInvalidMenu = menu.ErrInvalid
+
+	// ErrInvalidAccelerator is returned when the accelerator can't be parsed.
+	ErrInvalidAccelerator = accel.ErrInvalid
+
+	// ErrDuplicateAccelerator is returned when two MenuItem have the same accelerator.
+	ErrDuplicateAccelerator = menu.ErrDuplicate
+
+	// ErrInvalidFileFilter is returned when the FileFilter can't be parsed.
+	ErrInvalidFileFilter = filter.ErrInvalid
+
+	// ErrInvalidButtons is returned by MessageBox when the Buttons is unknown.
+	ErrInvalidButtons = errors.New("invalid buttons")
+)
+
+// HRESULTError is the error returned by the Windows APIs, such as WebView2.
+type HRESULTError struct {
+	// Op is the name of the function which fails.
+	Op string
+
+	// Code is the HRESULT returned by the function.
+	Code uint32
+}
+
+// hresults are the messages of the most common HRESULTs, used when the system doesn't provide one.
+var hresults = map[uint32]string{
+	0x80004001: "not implemented",
+	0x80004002: "no such interface supported",
+	0x80004003: "invalid pointer",
+	0x80004004: "operation aborted",
+	0x80004005: "unspecified failure",
+	0x8000FFFF: "unexpected failure",
+	0x80070002: "the system cannot find the file specified",
+	0x80070005: "access is denied",
+	0x8007000E: "not enough memory resources are available to complete this operation",
+	0x80070057: "the parameter is incorrect",
+	0x800700AA: "the requested resource is in use",
+	0x8007139F: "the group or resource is not in the correct state to perform the requested operation",
+}
+
+// Message returns the description of the HRESULT.
+func (e *HRESULTError) Message() string {
+	if msg := formatMessage(e.Code); msg != "" {
+		return msg
+	}
+
+	if msg, ok := hresults[e.Code]; ok {
+		return msg
+	}
+
+	return "unknown error"
+}
+
+func (e *HRESULTError) Error() string {
+	return fmt.Sprintf("%s fails with 0x%08x: %s", e.Op, e.Code, e.Message())
+}
+
+// hresult creates the HRESULTError for the given function.
+func hresult(op string, code uintptr) error {
+	return &HRESULTError{Op: op, Code: uint32(code)}
+}
+
+// JavaException is the exception thrown by Java, on Android.
+type JavaException struct {
+	// Class is the name of the exception, such as "java.lang.IllegalStateException".
+	Class string
+
+	// Message is the message of the exception, it might be empty.
+	Message string
+}
+
+func (e *JavaException) Error() string {
+	if e.Message == "" {
+		return e.Class
+	}
+	return e.Class + ": " + e.Message
+}
+
+// javaException converts the error of the JNI call into JavaException. The error is the Throwable.toString(), which
+// is formatted as "class: message".
+func javaException(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s := err.Error()
+	if i := strings.Index(s, ": "); i >= 0 {
+		return &JavaException{Class: s[:i], Message: s[i+2:]}
+	}
+
+	return &JavaException{Class: s}
+}
+
+// kindError classifies the error as one of the sentinel errors. Both the kind and the original error can be
+// checked using errors.Is and errors.As.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,8 @@
+//+build !windows
+
+package gowebview
+
+// formatMessage returns the message of the HRESULT, which is only available on Windows.
+func formatMessage(code uint32) string {
+	return ""
+}
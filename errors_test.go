@@ -0,0 +1,66 @@
+package gowebview
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestJavaException(t *testing.T) {
+	for _, test := range []struct {
+		err     error
+		class   string
+		message string
+		text    string
+	}{
+		{errors.New("java.lang.IllegalStateException: no activity"), "java.lang.IllegalStateException", "no activity", "java.lang.IllegalStateException: no activity"},
+		{errors.New("java.lang.NullPointerException"), "java.lang.NullPointerException", "", "java.lang.NullPointerException"},
+		{errors.New("java.io.IOException: open: no such file"), "java.io.IOException", "open: no such file", "java.io.IOException: open: no such file"},
+	} {
+		err := javaException(test.err)
+
+		var e *JavaException
+		if !errors.As(err, &e) {
+			t.Fatalf("%q: expected JavaException, got %T", test.err, err)
+		}
+		if e.Class != test.class || e.Message != test.message {
+			t.Errorf("%q: expected class %q and message %q, got %q and %q", test.err, test.class, test.message, e.Class, e.Message)
+		}
+		if err.Error() != test.text {
+			t.Errorf("%q: expected %q, got %q", test.err, test.text, err.Error())
+		}
+	}
+
+	if err := javaException(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+func TestKindError(t *testing.T) {
+	cause := &HRESULTError{Op: "CreateCoreWebView2Environment", Code: 0x80070002}
+	var err error = &kindError{kind: ErrRuntimeNotInstalled, err: cause}
+
+	// The error is usually wrapped again by the caller.
+	err = fmt.Errorf("new: %w", err)
+
+	if !errors.Is(err, ErrRuntimeNotInstalled) {
+		t.Error("errors.Is doesn't match the kind")
+	}
+	if errors.Is(err, ErrRuntimeVersion) {
+		t.Error("errors.Is matches other kind")
+	}
+
+	var hr *HRESULTError
+	if !errors.As(err, &hr) || hr != cause {
+		t.Error("errors.As doesn't find the original error")
+	}
+
+	other := &kindError{kind: ErrWindowClosed, err: os.ErrPermission}
+	if !errors.Is(other, ErrWindowClosed) || !errors.Is(other, os.ErrPermission) {
+		t.Error("errors.Is doesn't match both the kind and the original error")
+	}
+	if other.Error() != ErrWindowClosed.Error()+": "+os.ErrPermission.Error() {
+		t.Errorf("unexpected message %q", other.Error())
+	}
+}
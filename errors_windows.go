@@ -0,0 +1,17 @@
+package gowebview
+
+import (
+	"golang.org/x/sys/windows"
+	"strings"
+)
+
+// formatMessage returns the message of the HRESULT, provided by the system.
+func formatMessage(code uint32) string {
+	b := make([]uint16, 512)
+	n, err := windows.FormatMessage(windows.FORMAT_MESSAGE_FROM_SYSTEM|windows.FORMAT_MESSAGE_IGNORE_INSERTS, 0, code, 0, b, nil)
+	if err != nil || n == 0 {
+		return ""
+	}
+
+	return strings.TrimRight(windows.UTF16ToString(b[:n]), "\r\n. ")
+}
@@ -0,0 +1,15 @@
+package gowebview
+
+// FocusReason is how the keyboard focus moves into or out of the page, see WebView.Focus.
+type FocusReason int
+
+const (
+	// FocusProgrammatic focuses the element which was focused before.
+	FocusProgrammatic FocusReason = iota
+
+	// FocusNext focuses the first element of the page, as if the user pressed Tab.
+	FocusNext
+
+	// FocusPrevious focuses the last element of the page, as if the user pressed Shift+Tab.
+	FocusPrevious
+)
@@ -1,10 +1,13 @@
 package gowebview
 
 import (
+	"context"
 	"crypto/x509"
+	"image"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 //go:generate go run ./generator/generate.go
@@ -16,11 +19,27 @@ type WebView interface {
 	// you must destroy the webview.
 	Run()
 
+	// RunContext runs the main loop until it's terminated or the context is done, in that case the webview is
+	// destroyed. It returns the reason, such as ErrWindowClosed, ErrTerminated, ErrBrowserProcessFailed or the error
+	// of the context.
+	RunContext(ctx context.Context) error
+
 	// Terminate stops the main loop. It is safe to call this function from
 	// a background thread.
 	Terminate()
 
-	// Destroy destroys a webview and closes the native window.
+	// Dispatch runs the function on the UI thread, without waiting for it. It is safe to call this function from a
+	// background thread. On Android, the functions run on the main Looper, which is the UI thread of the Activity.
+	Dispatch(f func())
+
+	// DispatchSync runs the function on the UI thread and waits until it returns, the error of the function is
+	// returned. If the context is done before the function starts, the function never runs and the context error is
+	// returned. If the WebView is destroyed before that, it returns ErrClosed.
+	DispatchSync(ctx context.Context, f func() error) error
+
+	// Destroy destroys a webview and closes the native window. It waits until the native resources are released, it's
+	// safe to call it more than once. With Config.Ephemeral, it also waits until the profile is wiped, for up to a
+	// minute; if it still fails, the directory is removed on the next execution.
 	Destroy()
 
 	// Window returns a native window handle pointer. When using GTK backend the
@@ -32,7 +51,7 @@ type WebView interface {
 	// thread.
 	SetTitle(title string)
 
-	// SetSize updates native window size. See Hint constants.
+	// SetSize updates native window size, in logical units. See Hint constants.
 	SetSize(point *Point, hint Hint)
 
 	// Navigate navigates webview to the given URL. URL may be a data URI, i.e.
@@ -42,6 +61,133 @@ type WebView interface {
 	// SetVisibility updates the WindowMode, such as minimized or maximized
 	SetVisibility(v Visibility)
 
+	// SetFullscreen enters or leaves the fullscreen, see VisibilityFullscreen. The window also enters the fullscreen
+	// when the page requests it, such as the fullscreen button of a video, and leaves it when the page exits.
+	SetFullscreen(fullscreen bool)
+
+	// OnFullscreenChanged adds a function which is called when the window enters or leaves the fullscreen, either by
+	// SetFullscreen or by the page. The function is called from the UI thread.
+	OnFullscreenChanged(f func(fullscreen bool))
+
+	// SetIcon updates the icon of the window, displayed on the title bar and on the taskbar. The image is resized to
+	// the sizes used by the system, so a large image (such as 256x256) is recommended. The nil icon restores the
+	// icon of the executable. It returns the error if the image can't be encoded, and ErrFeatureNotSupported on
+	// Android.
+	SetIcon(icon image.Image) error
+
+	// SetProgress displays the progress on the taskbar button, the value is between 0 and 1. It's ignored on Android.
+	SetProgress(state ProgressState, value float64)
+
+	// SetBadge displays a small image over the taskbar button, such as the number of unread messages. The
+	// description is used by screen readers. If the badge is nil, it's removed. It returns the error if the image
+	// can't be encoded, and ErrFeatureNotSupported on Android.
+	SetBadge(badge image.Image, description string) error
+
+	// Focus moves the keyboard focus into the page. That is useful when the WebView is embedded into another
+	// window, see WindowConfig.Window. It's ignored on Android.
+	Focus(reason FocusReason)
+
+	// OnFocusLeave adds a function which is called when the user moves the keyboard focus out of the page, using Tab
+	// or Shift+Tab, so the host can focus its next or previous control. Without any function, the focus cycles inside
+	// the page. The function is called from the UI thread. It's ignored on Android.
+	OnFocusLeave(f func(reason FocusReason))
+
+	// SetMenu displays the menu bar of the window, the nil Menu removes it. The Menu must not be changed after that,
+	// call SetMenu again instead. The frameless windows don't display the menu bar, but the accelerators work. It
+	// returns ErrFeatureNotSupported on Android and when the WebView is embedded into WindowConfig.Window.
+	SetMenu(menu *Menu) error
+
+	// SetContextMenu replaces or extends the context menu displayed when the user right-clicks the page, see
+	// ContextMenuMode. The pages which handle the contextmenu event keep their own menu. It returns
+	// ErrFeatureNotSupported on Android.
+	SetContextMenu(menu *Menu, mode ContextMenuMode) error
+
+	// RegisterShortcut calls the function when the user presses the keys, such as "Ctrl+Shift+I", while the page is
+	// focused. The browser doesn't handle the keys, even when it's one of its accelerators, such as "F5". The nil
+	// function removes the shortcut. The format is the same of MenuItem.Accelerator, the shortcut takes precedence
+	// over the menu. The function is called from the UI thread. It returns ErrFeatureNotSupported on Android.
+	RegisterShortcut(chord string, f func()) error
+
+	// OpenFileDialog displays the native dialog to select existing files, and blocks until the user closes it. It
+	// returns the selected files, or nil if the user cancels it. On Android, the files are content URIs. The dialog
+	// is also used by the <input type="file"> of the page.
+	OpenFileDialog(options *FileDialogOptions) ([]string, error)
+
+	// SaveFileDialog displays the native dialog to choose the file to save, and blocks until the user closes it. It
+	// returns the file, or an empty string if the user cancels it. On Android, the file is a content URI, which is
+	// created by the dialog.
+	SaveFileDialog(options *FileDialogOptions) (string, error)
+
+	// MessageBox displays the native message box, and blocks until the user clicks one of the buttons.
+	MessageBox(title, text string, buttons Buttons) (Button, error)
+
+	// Notify displays the notification of the operating system. On Windows, the notification belongs to the Tray,
+	// if any, otherwise a temporary icon is added to the notification area. On Android, the application needs the
+	// POST_NOTIFICATIONS permission. The org.freedesktop.Notifications of Linux is pending, see the README.
+	Notify(notification Notification) error
+
+	// SetNotificationsAllowed grants or denies the permission of the Notifications API of the page, see
+	// WindowConfig.AllowNotifications. It's ignored on Android.
+	SetNotificationsAllowed(allowed bool)
+
+	// Clipboard returns the clipboard of the operating system, which is shared by all windows.
+	Clipboard() Clipboard
+
+	// Bounds returns the position and size of the native window, including the borders and the title bar, in pixels,
+	// like the Screens.
+	Bounds() Rect
+
+	// SetBounds updates the position and size of the native window, including the borders and the title bar, in
+	// pixels, like the Screens. It's ignored on Android, where the View is placed by its layout, Bounds still reports
+	// where it is.
+	SetBounds(bounds Rect)
+
+	// Scale returns the scale factor of the monitor where the window is, such as 1.5 when the DPI is 144. The size
+	// in pixels is the size in logical units multiplied by the Scale.
+	Scale() float64
+
+	// Zoom returns the zoom factor of the page, such as 1.25 for 125%. On Android, it's the factor of SetZoom,
+	// which is the text zoom, multiplied by the pinch zoom.
+	Zoom() float64
+
+	// SetZoom updates the zoom factor of the page, such as 1.25 for 125%. It's independent of the Scale, and it
+	// works even if the WindowConfig.DisableZoom is true.
+	SetZoom(factor float64)
+
+	// OnZoomChanged adds a function which is called when the zoom factor changes, either by the user or by SetZoom,
+	// including the pinch zoom. The function is called from the UI thread.
+	OnZoomChanged(f func(factor float64))
+
+	// Center moves the native window to the center of the work area of the current screen. It's ignored on Android,
+	// where the View is placed by its layout.
+	Center()
+
+	// Screens returns all screens (monitors) connected, the primary screen is the first one.
+	Screens() []Screen
+
+	// ClearBrowsingData removes the given kinds of data of all origins from the Profile used by the WebView. It blocks
+	// until the data is removed or the context is done. If since is non-zero, only data created after that time is
+	// removed. On Windows, the DataAll also removes the autofill, saved passwords and site settings. Older WebView2
+	// runtimes can only clear the storage of the current page, then it returns an error matching
+	// ErrFeatureNotSupported, so Profile.Remove must be used to be sure that nothing remains. Android doesn't support
+	// since, and returns ErrFeatureNotSupported.
+	ClearBrowsingData(ctx context.Context, kinds DataKinds, since time.Time) error
+
+	// OnProcessFailed adds a function which is called when the browser process fails, such as when it crashes. The
+	// function is called from the UI thread.
+	OnProcessFailed(f func(failure ProcessFailure))
+
+	// OnCloseRequested adds a function which is called when the user tries to close the window, or when the page
+	// calls `window.close()`. If any function returns false, the window is kept open. The function is called from the
+	// UI thread. Terminate and Destroy always close the window.
+	OnCloseRequested(f func() bool)
+
+	// OnCloseRequestedAsync is similar to OnCloseRequested, but the decision is given later by calling reply, which
+	// is safe to call from a background thread. That can be used to ask the page if it has unsaved changes. The
+	// window is kept open until all functions allow it to close. If any function doesn't reply within 30 seconds,
+	// the request is denied, and the next attempt to close the window asks all functions again.
+	OnCloseRequestedAsync(f func(reply func(allow bool)))
+
 	// Init injects JavaScript code at the initialization of the new page. Every
 	// time the webview will open a the new page - this initialization code will
 	// be executed. It is guaranteed that code is executed before window.onload.
@@ -56,6 +202,16 @@ type WebView interface {
 // New calls NewWindow to create a new window and a new webview instance. If debug
 // is non-zero - developer tools will be enabled (if the platform supports them).
 func New(config *Config) (WebView, error) {
+	config, err := setDefaults(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWindow(config)
+}
+
+// setDefaults fills the missing values of the Config, the given Config is modified.
+func setDefaults(config *Config) (*Config, error) {
 	if config == nil {
 		config = new(Config)
 	}
@@ -69,23 +225,56 @@ func New(config *Config) (WebView, error) {
 	}
 
 	if config.WindowConfig.Title == "" {
-		dir, err := os.Executable()
-		if err != nil {
-			dir = "gowebview"
-		}
-		filename := filepath.Base(filepath.Clean(dir))
-		config.WindowConfig.Title = strings.Title(strings.TrimSuffix(filename, filepath.Ext(filename)))
+		config.WindowConfig.Title = strings.Title(appName())
 	}
 
 	if config.WindowConfig.Size == nil {
 		config.WindowConfig.Size = &Point{X: 600, Y: 600}
 	}
 
-	if config.WindowConfig.Path == "" {
-		config.WindowConfig.Path = filepath.Join(os.TempDir(), config.WindowConfig.Title)
+	if config.LibraryDir == "" {
+		config.LibraryDir = config.WindowConfig.Path
 	}
 
-	return newWindow(config)
+	if config.LibraryDir == "" {
+		config.LibraryDir = filepath.Join(os.TempDir(), config.WindowConfig.Title)
+	}
+
+	if config.UserDataDir == "" {
+		config.UserDataDir = config.WindowConfig.Path
+	}
+
+	if config.UserDataDir == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		config.UserDataDir = filepath.Join(dir, appName())
+	}
+
+	if config.Ephemeral {
+		p, err := newEphemeralProfile()
+		if err != nil {
+			return nil, err
+		}
+		config.Profile = p
+	}
+
+	if config.Profile == nil {
+		config.Profile = NewProfile(config.UserDataDir)
+	}
+
+	return config, nil
+}
+
+// appName returns the name of the executable, without the extension.
+func appName() string {
+	dir, err := os.Executable()
+	if err != nil {
+		dir = "gowebview"
+	}
+	filename := filepath.Base(filepath.Clean(dir))
+	return strings.TrimSuffix(filename, filepath.Ext(filename))
 }
 
 
@@ -98,6 +287,43 @@ type Config struct {
 	// TransportConfig keeps configurations about the network traffic
 	TransportConfig *TransportConfig
 
+	// UserDataDir defines where the browser data (cookies, cache, storage and history) is stored. By default it's
+	// the `os.UserConfigDir()/<app>`, where <app> is the name of the executable. See MigrateUserData to move the data
+	// from the previous location.
+	UserDataDir string
+
+	// LibraryDir defines where the DLL (or equivalent) will be extracted. By default it's `os.TempDir()/<Title>`.
+	LibraryDir string
+
+	// Profile defines where the cookies, cache, storage and history are stored. The same Profile can be shared by
+	// multiple WebViews. If nil, it will use the UserDataDir.
+	Profile *Profile
+
+	// Ephemeral if true the WebView will use a new and empty Profile, on a random directory, which is wiped when the
+	// WebView is destroyed. When used with NewApp, all windows share the Profile, which is wiped when the App is
+	// terminated. The UserDataDir and Profile are ignored. Directories left by crashes are removed on the next
+	// execution. It returns ErrFeatureNotSupported on Android, which uses the same profile for the entire app.
+	Ephemeral bool
+
+	// BrowserExecutableDir defines the directory of a fixed-version browser runtime, which is shipped with the app,
+	// such as the "Microsoft.WebView2.FixedVersionRuntime.<version>.x64" directory on Windows. If empty, it uses the
+	// installed runtime. It's only supported on Windows.
+	BrowserExecutableDir string
+
+	// ReleaseChannel defines which release channel of the installed runtime is used, when more than one is
+	// installed. It's ignored when the BrowserExecutableDir is defined. It's only supported on Windows.
+	ReleaseChannel ReleaseChannel
+
+	// MinimumRuntimeVersion defines the minimum version of the browser runtime, such as "86.0.616.0". If the runtime
+	// is older, it returns ErrRuntimeVersion. If empty, or if the installed version is unknown, any version is
+	// accepted, like the BootstrapConfig.MinimumVersion. It returns ErrInvalidVersion if it can't be parsed. It's
+	// only supported on Windows.
+	MinimumRuntimeVersion string
+
+	// AutoRecover if non-nil the WebView is recreated, and the last URL is reloaded, when the browser process fails.
+	// If nil, the RunContext returns ErrBrowserProcessFailed when the browser can't be used anymore.
+	AutoRecover *RecoverPolicy
+
 	// URL defines the default page.
 	URL string
 
@@ -105,16 +331,79 @@ type Config struct {
 	Debug bool
 }
 
+// ReleaseChannel defines the preference of the release channel of the browser runtime.
+type ReleaseChannel int
+
+const (
+	// ReleaseChannelStable prefers the most stable channel: stable, beta, dev and then canary.
+	ReleaseChannelStable ReleaseChannel = iota
+	// ReleaseChannelCanary prefers the least stable channel: canary, dev, beta and then stable. That is useful to
+	// test upcoming versions.
+	ReleaseChannelCanary
+)
+
 // WindowConfig describes topics related to the Window/View.
 type WindowConfig struct {
 
 	// Title defines the title of the window.
 	Title string
 
-	// Size defines the Width x Height of the window.
+	// Size defines the Width x Height of the window, in logical units. The logical unit is scaled by the scale
+	// factor of the monitor, so 600 is 900 pixels on a monitor with 150% scale.
 	Size *Point
 
-	// Path defines the path where the DLL will be exported.
+	// Position defines the X x Y coordinates of the top-left corner of the window, in pixels, relative to the
+	// primary screen.
+	// If nil, the operating system chooses the position. See Screens to open on a specific screen.
+	Position *Point
+
+	// StateStore defines where the position, size and visibility of the window are saved when it's closed. The saved
+	// state is restored when the window is created, overriding the Size, Position and VisibilityDefault, and clamped
+	// to the attached screens so the window never reopens off-screen. See StateFile. If the state can't be saved, the
+	// RunContext returns the error wrapped with the reason, which still matches the reason using errors.Is. It's
+	// ignored on Android.
+	StateStore StateStore
+
+	// Style defines the appearance and behavior of the window, such as StyleFrameless. It's only supported on
+	// Windows.
+	Style WindowStyle
+
+	// Theme defines the colors of the title bar, by default it follows the preference of the user. It's only
+	// supported on Windows.
+	Theme Theme
+
+	// DisableZoom if true the user can't change the zoom of the page, using pinch, Ctrl+wheel or Ctrl+Plus/Minus.
+	// That is useful for kiosks. The SetZoom still works.
+	DisableZoom bool
+
+	// DisableAccelerators are the keyboard shortcuts of the browser which are ignored, such as AcceleratorsReload
+	// or AcceleratorsPrint, see RegisterShortcut. It's ignored on Android.
+	DisableAccelerators BrowserAccelerators
+
+	// AllowNotifications grants the permission of the Notifications API of the page, the notifications are displayed
+	// by Notify. Otherwise, the permission is denied. The user isn't asked, the Notification.requestPermission
+	// resolves to the current permission, which can be changed by SetNotificationsAllowed. The show, click and
+	// close events are sent to the Notification of the page.
+	//
+	// It's ignored on Android, the Android WebView doesn't provide the Notifications API to the page, use Notify
+	// instead.
+	AllowNotifications bool
+
+	// AllowClipboardWrite lets the page write text to the clipboard when the Clipboard API of the browser rejects
+	// it, such as when the page doesn't have the focus. The navigator.clipboard.writeText falls back to
+	// Clipboard.WriteText, only with the user gesture, on the top-level document of secure pages (HTTPS or
+	// localhost) and from the origin of the Config.URL or one of the ClipboardOrigins. The host can't tell which
+	// script wrote the text, it only requires a key press or click of the user on the window within the last 5
+	// seconds. Any script of an allowed origin can write to the clipboard in that time, so only allow trusted
+	// origins. It's ignored on Android.
+	AllowClipboardWrite bool
+
+	// ClipboardOrigins are the origins, such as "https://example.com", also allowed to use the AllowClipboardWrite.
+	ClipboardOrigins []string
+
+	// Path defines the path where the DLL will be exported and the browser data is stored.
+	//
+	// Deprecated: use Config.LibraryDir and Config.UserDataDir instead. If set, it's used as the default of both.
 	Path string
 
 	// Visibility defines how the page must open.
@@ -122,10 +411,18 @@ type WindowConfig struct {
 
 	// Window defines the window handle (GtkWindow, NSWindow, HWND pointer or View pointer for Android).
 	// For Gio (Android):  it MUST point to `e.View` from `app.ViewEvent`
+	// For Windows: if non-zero, the WebView is embedded as a child of the given HWND, instead of creating a new
+	// window. The Position is relative to the client area of the parent, and so are Bounds and SetBounds. The Style,
+	// Theme, StateStore and the fullscreen are ignored. Use Focus and OnFocusLeave to move the keyboard focus between
+	// the WebView and the host. The WebView runs on its own thread, and the parent is sent messages by that thread,
+	// so DispatchSync and the other synchronous calls must not be made from the thread of the parent window: it
+	// deadlocks, since the parent can't process them while it waits. The DPI awareness of the process is also left to
+	// the host.
 	Window uintptr
 
 	// VM defines the JNI VM for Android
 	// For Gio (Android):  it MUST point to `app.JavaVM()`
+	// On Android, New returns ErrMissingView if the VM or the Window is zero.
 	VM uintptr
 }
 
@@ -186,12 +483,20 @@ const (
 	VisibilityDefault Visibility = iota
 
 	// VisibilityMaximized will open the window as maximized
-	// Windowless systems (like Android) will open as fullscreen
+	// Windowless systems (like Android) will fill the screen, keeping the system bars visible
 	VisibilityMaximized
 
 	// VisibilityMinimized will open the window as minimized
 	// Windowless systems (like Android) will hides the webview (return the previous view)
 	VisibilityMinimized
+
+	// VisibilityFullscreen will open the window as fullscreen, covering the entire screen, without borders and
+	// taskbar. Windowless systems (like Android) will also hide the system bars
+	VisibilityFullscreen
+
+	// VisibilityHidden will hide the window, including the taskbar button, it's usually displayed again by the Tray.
+	// Windowless systems (like Android) will hides the webview, the same of VisibilityMinimized
+	VisibilityHidden
 )
 
 // Point are used to configure the size or coordinates.
@@ -199,6 +504,32 @@ type Point struct {
 	X, Y int64
 }
 
+// Rect are used to configure the position and size, in pixels. The Scale converts the pixels into logical units.
+type Rect struct {
+	X, Y          int64
+	Width, Height int64
+}
+
+// Contains returns true if the point is inside the Rect.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
+
+// Screen describes one screen (monitor).
+type Screen struct {
+	// Bounds is the area of the screen, in pixels, relative to the primary screen.
+	Bounds Rect
+
+	// WorkArea is the area of the screen without the taskbar and docked toolbars.
+	WorkArea Rect
+
+	// Scale is the scale factor defined by the user, such as 1.5 when the DPI is 144.
+	Scale float64
+
+	// Primary is true if it's the primary screen.
+	Primary bool
+}
+
 // HTTPProxy are used to configure the Proxy
 type HTTPProxy struct {
 	IP   string
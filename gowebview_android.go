@@ -3,14 +3,27 @@
 package gowebview
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/base64"
-	"errors"
 	"git.wow.st/gmp/jni"
+	"github.com/inkeliz/gowebview/internal/closing"
+	"github.com/inkeliz/gowebview/internal/dispatch"
+	"github.com/inkeliz/gowebview/internal/filter"
+	"github.com/inkeliz/gowebview/internal/recovery"
+	"image"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 	"unsafe"
 )
 
+// The jar must be generated again, and committed, whenever the gowebview_android.java changes: the methods are looked
+// up by name at runtime, the missing ones fail with NoSuchMethodError.
 //go:generate javac -source 8 -target 8 -bootclasspath $ANDROID_HOME\platforms\android-30\android.jar -d $TEMP\gowebview\classes gowebview_android.java
 //go:generate jar cf gowebview_android.jar -C $TEMP\gowebview\classes .
 
@@ -21,21 +34,89 @@ type webview struct {
 	clsWebView jni.Class
 	objWebView jni.Object
 
-	closed chan bool
-	mutex  *sync.Mutex
+	done   chan struct{}
+	reason error
+	once   sync.Once
+	mutex  *sync.RWMutex
+	queue  *dispatch.Queue
 
-	config *Config
+	// handle identifies the queue on `webview_wake`, and uiThread is the thread id of the Android UI thread.
+	handle   int64
+	uiThread int
+
+	config   *Config
+	release  func()
+	onClosed func()
+
+	events          chan struct{}
+	backoff         *recovery.Backoff
+	onProcessFailed []func(failure ProcessFailure)
+	onClose         closing.Handlers
+	onZoomChanged   []func(factor float64)
+
+	onFullscreenChanged []func(fullscreen bool)
+
+	// dialogs is kinda of `map[request]chan result`, the request zero is the <input type="file">.
+	dialogs  map[int]chan string
+	dialogID int
+}
+
+type application struct{}
+
+func newApplication(config *Config) (*application, error) {
+	if err := checkEphemeral(config); err != nil {
+		return nil, err
+	}
+
+	return new(application), nil
+}
+
+func (a *application) newWindow(config *Config, closed func()) (WebView, error) {
+	return newWebview(config, closed)
+}
+
+func (a *application) terminate() {
+	return
+}
+
+func (a *application) newTray(config *TrayConfig) (Tray, error) {
+	return nil, ErrFeatureNotSupported
 }
 
 func newWindow(config *Config) (wv WebView, err error) {
+	if err := checkEphemeral(config); err != nil {
+		return nil, err
+	}
+
+	return newWebview(config, nil)
+}
+
+// checkEphemeral returns ErrFeatureNotSupported if the Config.Ephemeral is used. Android uses one profile for the
+// entire app, so the data of the ephemeral profile can't be removed without touching the persistent one.
+func checkEphemeral(config *Config) error {
+	if !config.Ephemeral {
+		return nil
+	}
+
+	config.Profile.Remove(context.Background())
+	return ErrFeatureNotSupported
+}
+
+func newWebview(config *Config, closed func()) (wv WebView, err error) {
 	w := &webview{
-		closed: make(chan bool),
-		mutex:  new(sync.Mutex),
-		config: config,
+		done:     make(chan struct{}),
+		mutex:    new(sync.RWMutex),
+		config:   config,
+		onClosed: closed,
 	}
 
 	if config.WindowConfig.VM == 0 || config.WindowConfig.Window == 0 {
-		return
+		return nil, ErrMissingView
+	}
+
+	// Android uses one profile for the entire app, the Profile is only used to know when it can be removed.
+	if w.release, err = config.Profile.acquire(); err != nil {
+		return nil, err
 	}
 
 	w.vm = jni.JVMFor(config.WindowConfig.VM)
@@ -49,7 +130,7 @@ func newWindow(config *Config) (wv WebView, err error) {
 		mid := jni.GetMethodID(env, cls, "getClass", "()Ljava/lang/Class;")
 		obj, err := jni.CallObjectMethod(env, obj, mid)
 		if err != nil {
-			panic(err)
+			return javaException(err)
 		}
 
 		// Run getClassLoader() to get the ClassLoader
@@ -57,7 +138,7 @@ func newWindow(config *Config) (wv WebView, err error) {
 		mid = jni.GetMethodID(env, cls, "getClassLoader", "()Ljava/lang/ClassLoader;")
 		obj, err = jni.CallObjectMethod(env, obj, mid)
 		if err != nil {
-			panic(err)
+			return javaException(err)
 		}
 
 		// Run findClass() to get the custom class (in that case com.inkeliz.gowebview.gowebview_android, that name
@@ -66,7 +147,7 @@ func newWindow(config *Config) (wv WebView, err error) {
 		mid = jni.GetMethodID(env, cls, "findClass", "(Ljava/lang/String;)Ljava/lang/Class;")
 		clso, err := jni.CallObjectMethod(env, obj, mid, jni.Value(jni.JavaString(env, `com.inkeliz.gowebview.gowebview_android`)))
 		if err != nil {
-			panic(err)
+			return javaException(err)
 		}
 
 		// We need to create an GlobalRef of our class, otherwise we can't manipulate that afterwards.
@@ -77,7 +158,7 @@ func newWindow(config *Config) (wv WebView, err error) {
 		mid = jni.GetMethodID(env, w.clsWebView, "<init>", `()V`)
 		obj, err = jni.NewObject(env, w.clsWebView, mid)
 		if err != nil {
-			panic(err)
+			return javaException(err)
 		}
 
 		// We need to create an GlobalRef of our object.
@@ -88,16 +169,51 @@ func newWindow(config *Config) (wv WebView, err error) {
 		mid = jni.GetMethodID(env, w.clsWebView, "webview_create", "(Landroid/view/View;)V")
 		err = jni.CallVoidMethod(env, w.objWebView, mid, jni.Value(w.view))
 		if err != nil {
-			panic(err)
+			return javaException(err)
+		}
+
+		// The thread id of the UI thread is used by DispatchSync, which runs immediately on the UI thread.
+		mid = jni.GetMethodID(env, w.clsWebView, "webview_ui_thread", "()I")
+		tid, err := jni.CallIntMethod(env, w.objWebView, mid)
+		if err != nil {
+			return javaException(err)
 		}
+		w.uiThread = int(tid)
 
 		return nil
 	})
 
 	if err != nil {
+		// The global references are created before the calls which may fail.
+		jni.Do(w.vm, func(env jni.Env) error {
+			if w.objWebView != 0 {
+				jni.DeleteGlobalRef(env, w.objWebView)
+			}
+			if w.clsWebView != 0 {
+				jni.DeleteGlobalRef(env, jni.Object(w.clsWebView))
+			}
+			return nil
+		})
+		w.objWebView, w.clsWebView = 0, 0
+
+		w.release()
 		return nil, err
 	}
 
+	w.newQueue()
+
+	if config.AutoRecover != nil {
+		w.backoff = recovery.New(recovery.Policy(*config.AutoRecover))
+	}
+
+	w.events = make(chan struct{})
+	w.dialogs = make(map[int]chan string)
+	go w.pump(w.clsWebView, w.objWebView)
+
+	if config.WindowConfig.DisableZoom {
+		w.call("webview_disable_zoom", "()V")
+	}
+
 	w.SetURL(config.URL)
 	w.setProxy(config.TransportConfig.Proxy)
 	w.setCerts(config.TransportConfig.CertificateAuthorities)
@@ -106,24 +222,41 @@ func newWindow(config *Config) (wv WebView, err error) {
 }
 
 func (w *webview) Run() {
+	w.RunContext(context.Background())
+}
+
+func (w *webview) RunContext(ctx context.Context) error {
 	w.call("webview_run", "()V")
-	<-w.closed
+
+	select {
+	case <-w.done:
+		return w.reason
+	case <-ctx.Done():
+		w.Destroy()
+		return ctx.Err()
+	}
 }
 
 func (w *webview) Terminate() {
-	return
+	w.call("webview_hide", "()V")
+	w.finish(ErrTerminated)
 }
 
 func (w *webview) Destroy() {
-	w.call("webview_destroy", "()V")
+	// It waits until the WebView is destroyed on the UI thread.
+	err := w.call("webview_destroy", "()V")
 
 	w.mutex.Lock()
-	defer w.mutex.Unlock()
-
 	if w.objWebView == 0 || w.clsWebView == 0 {
+		w.mutex.Unlock()
 		return
 	}
 
+	// The pump uses the global references, until it receives the "closed" event.
+	if err == nil {
+		<-w.events
+	}
+
 	jni.Do(w.vm, func(env jni.Env) error {
 		jni.DeleteGlobalRef(env, jni.Object(w.clsWebView))
 		jni.DeleteGlobalRef(env, w.objWebView)
@@ -132,7 +265,347 @@ func (w *webview) Destroy() {
 	})
 
 	w.objWebView, w.clsWebView = 0, 0
-	w.closed <- true
+	w.mutex.Unlock()
+
+	w.release()
+	w.queue.Close()
+	queues.Delete(w.handle)
+	w.finish(ErrTerminated)
+
+	if w.onClosed != nil {
+		w.onClosed()
+	}
+}
+
+// finish stops the Run with the given reason, only the first reason is used.
+func (w *webview) finish(reason error) {
+	w.once.Do(func() {
+		w.reason = reason
+		close(w.done)
+	})
+}
+
+// pump receives the events from Java, until the WebView is destroyed. The events are handled on the UI thread.
+func (w *webview) pump(cls jni.Class, obj jni.Object) {
+	defer close(w.events)
+
+	for {
+		var event string
+		err := jni.Do(w.vm, func(env jni.Env) error {
+			// It blocks until the next event, see `webview_event` at gowebview_android.java.
+			o, err := jni.CallObjectMethod(env, obj, jni.GetMethodID(env, cls, "webview_event", "()Ljava/lang/String;"))
+			if err != nil {
+				return javaException(err)
+			}
+			defer jni.DeleteLocalRef(env, o)
+
+			event = jni.GoString(env, jni.String(o))
+			return nil
+		})
+		if err != nil || event == "closed" {
+			return
+		}
+
+		name, arg := event, ""
+		if i := strings.IndexByte(event, ':'); i >= 0 {
+			name, arg = event[:i], event[i+1:]
+		}
+
+		w.queue.Dispatch(func() {
+			w.event(name, arg)
+		})
+	}
+}
+
+// event handles the event from Java, it must be called from the UI thread.
+func (w *webview) event(name, arg string) {
+	switch name {
+	case "process_failed":
+		// The arg is "crash" or "killed", see onRenderProcessGone.
+		kind := ProcessFailureRenderExited
+		if arg == "killed" {
+			kind = ProcessFailureRenderKilled
+		}
+		w.processFailed(ProcessFailure{Kind: kind, ExitCode: -1})
+	case "close_requested":
+		w.onClose.Request(w.Dispatch, func() {
+			w.finish(ErrWindowClosed)
+
+			// Destroy waits for the pump, which can't happen on the UI thread.
+			go w.Destroy()
+		})
+	case "fullscreen_changed":
+		for _, f := range w.onFullscreenChanged {
+			f(arg == "true")
+		}
+	case "zoom_changed":
+		percent, _ := strconv.Atoi(arg)
+		for _, f := range w.onZoomChanged {
+			f(float64(percent) / 100)
+		}
+	case "file_chooser":
+		// The arg is "multiple:accept", such as "true:image/*,.pdf".
+		i := strings.IndexByte(arg, ':')
+		if i < 0 {
+			return
+		}
+
+		var mimes []string
+		if f, ok := filter.FromAccept(arg[i+1:]); ok {
+			mimes = filter.MIMETypes([]filter.Filter{f})
+		}
+		go w.choose(0, mimes, arg[:i] == "true", false, "")
+	case "files_chosen", "message_box":
+		// The arg is "request:result", such as the URIs separated by new lines or the button.
+		i := strings.IndexByte(arg, ':')
+		if i < 0 {
+			return
+		}
+
+		id, _ := strconv.Atoi(arg[:i])
+		result, ok := w.dialogs[id]
+		if !ok {
+			return
+		}
+		delete(w.dialogs, id)
+
+		result <- arg[i+1:]
+	}
+}
+
+func (w *webview) OpenFileDialog(options *FileDialogOptions) ([]string, error) {
+	if options == nil {
+		options = new(FileDialogOptions)
+	}
+
+	return w.fileDialog(filter.MIMETypes(options.filters()), options.Multiple, false, options.Filename)
+}
+
+func (w *webview) SaveFileDialog(options *FileDialogOptions) (string, error) {
+	if options == nil {
+		options = new(FileDialogOptions)
+	}
+
+	// The document is created with the type of the first pattern.
+	var mimes []string
+	if f := options.filters(); len(f) > 0 && len(f[0].Patterns) > 0 {
+		mimes = filter.MIMETypes([]filter.Filter{{Patterns: f[0].Patterns[:1]}})
+	}
+
+	files, err := w.fileDialog(mimes, false, true, options.Filename)
+	if len(files) == 0 {
+		return "", err
+	}
+	return files[0], err
+}
+
+// fileDialog displays the document picker, and blocks until the user closes it.
+func (w *webview) fileDialog(mimes []string, multiple, save bool, filename string) ([]string, error) {
+	uris, err := w.dialog(func(id int) error {
+		return w.choose(id, mimes, multiple, save, filename)
+	})
+	if err != nil || uris == "" {
+		return nil, err
+	}
+	return strings.Split(uris, "\n"), nil
+}
+
+// dialog calls the function with a new request, and blocks until the Java answers the request.
+func (w *webview) dialog(f func(id int) error) (string, error) {
+	result := make(chan string, 1)
+
+	var id int
+	err := w.queue.DispatchSync(context.Background(), func() error {
+		w.dialogID++
+		id = w.dialogID
+		w.dialogs[id] = result
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := f(id); err != nil {
+		w.queue.Dispatch(func() {
+			delete(w.dialogs, id)
+		})
+		return "", err
+	}
+
+	select {
+	case s := <-result:
+		return s, nil
+	case <-w.events:
+		return "", ErrClosed
+	}
+}
+
+func (w *webview) MessageBox(title, text string, buttons Buttons) (Button, error) {
+	if buttons < ButtonsOK || buttons > ButtonsRetryCancel {
+		return ButtonNone, ErrInvalidButtons
+	}
+
+	s, err := w.dialog(func(id int) error {
+		return w.callArgs("webview_message_box", "(ILjava/lang/String;Ljava/lang/String;I)V", func(env jni.Env) []jni.Value {
+			return []jni.Value{
+				jni.Value(id),
+				jni.Value(jni.JavaString(env, title)),
+				jni.Value(jni.JavaString(env, text)),
+				jni.Value(buttons),
+			}
+		})
+	})
+	if err != nil {
+		return ButtonNone, err
+	}
+
+	button, _ := strconv.Atoi(s)
+	return Button(button), nil
+}
+
+func (w *webview) Clipboard() Clipboard {
+	return &clipboard{webview: w}
+}
+
+// clipboard uses the ClipboardManager, the images aren't supported since they need a content provider.
+type clipboard struct {
+	webview *webview
+}
+
+func (c *clipboard) ReadText() (string, error) {
+	return c.webview.callString("webview_clipboard_read", "()Ljava/lang/String;")
+}
+
+func (c *clipboard) WriteText(text string) error {
+	return c.webview.callArgs("webview_clipboard_write", "(Ljava/lang/String;)V", func(env jni.Env) []jni.Value {
+		return []jni.Value{
+			jni.Value(jni.JavaString(env, text)),
+		}
+	})
+}
+
+func (c *clipboard) ReadImage() (image.Image, error) {
+	return nil, ErrFeatureNotSupported
+}
+
+func (c *clipboard) WriteImage(img image.Image) error {
+	return ErrFeatureNotSupported
+}
+
+func (w *webview) Notify(n Notification) error {
+	return w.callArgs("webview_notify", "(Ljava/lang/String;Ljava/lang/String;)V", func(env jni.Env) []jni.Value {
+		return []jni.Value{
+			jni.Value(jni.JavaString(env, n.Title)),
+			jni.Value(jni.JavaString(env, n.Body)),
+		}
+	})
+}
+
+func (w *webview) SetNotificationsAllowed(allowed bool) {
+	return
+}
+
+// choose calls the `webview_choose`, see gowebview_android.java.
+func (w *webview) choose(request int, mimes []string, multiple, save bool, filename string) error {
+	return w.callArgs("webview_choose", "(ILjava/lang/String;ZZLjava/lang/String;)V", func(env jni.Env) []jni.Value {
+		var m, s jni.Value
+		if multiple {
+			m = 1
+		}
+		if save {
+			s = 1
+		}
+		return []jni.Value{
+			jni.Value(request),
+			jni.Value(jni.JavaString(env, strings.Join(mimes, ","))),
+			m,
+			s,
+			jni.Value(jni.JavaString(env, filename)),
+		}
+	})
+}
+
+func (w *webview) OnZoomChanged(f func(factor float64)) {
+	w.queue.Dispatch(func() {
+		w.onZoomChanged = append(w.onZoomChanged, f)
+	})
+}
+
+func (w *webview) OnCloseRequested(f func() bool) {
+	w.queue.Dispatch(func() {
+		w.onClose.Sync = append(w.onClose.Sync, f)
+	})
+}
+
+func (w *webview) OnCloseRequestedAsync(f func(reply func(allow bool))) {
+	w.queue.Dispatch(func() {
+		w.onClose.Async = append(w.onClose.Async, f)
+	})
+}
+
+func (w *webview) OnProcessFailed(f func(failure ProcessFailure)) {
+	w.queue.Dispatch(func() {
+		w.onProcessFailed = append(w.onProcessFailed, f)
+	})
+}
+
+// processFailed notifies the failure and tries to recover, if the Config.AutoRecover is defined. Android can't reuse
+// the WebView after the render process is gone, so the WebView stops if it can't be recovered.
+func (w *webview) processFailed(failure ProcessFailure) {
+	var delay time.Duration
+	if w.backoff != nil {
+		delay, failure.Recovering = w.backoff.Failed(time.Now())
+	}
+
+	for _, f := range w.onProcessFailed {
+		f(failure)
+	}
+
+	if !failure.Recovering {
+		w.finish(ErrBrowserProcessFailed)
+		return
+	}
+
+	time.AfterFunc(delay, func() {
+		w.call("webview_recover", "()V")
+	})
+}
+
+func (w *webview) Dispatch(f func()) {
+	w.queue.Dispatch(f)
+}
+
+func (w *webview) DispatchSync(ctx context.Context, f func() error) error {
+	return w.queue.DispatchSync(ctx, f)
+}
+
+// queues are the queues of Dispatch, by the handle given to `webview_wake`.
+var (
+	queues      sync.Map
+	queueHandle int64
+)
+
+// newQueue creates the queue used by Dispatch. The functions run on the Android UI thread, which is woken up by
+// `webview_wake` and calls drain, see gowebview_android.java.
+func (w *webview) newQueue() {
+	w.handle = atomic.AddInt64(&queueHandle, 1)
+	w.queue = dispatch.New(func() {
+		w.callArgs("webview_wake", "(J)V", func(env jni.Env) []jni.Value {
+			return []jni.Value{
+				jni.Value(w.handle),
+			}
+		})
+	}, func() bool {
+		return syscall.Gettid() == w.uiThread
+	})
+	queues.Store(w.handle, w.queue)
+}
+
+// drain runs the functions of the queue, it's called by `webview_drain` on the Android UI thread.
+func drain(handle int64) {
+	if queue, ok := queues.Load(handle); ok {
+		queue.(*dispatch.Queue).Drain()
+	}
 }
 
 func (w *webview) Window() uintptr {
@@ -161,13 +634,155 @@ func (w *webview) SetURL(url string) {
 
 func (w *webview) SetVisibility(v Visibility) {
 	switch v {
-	case VisibilityMinimized:
+	case VisibilityMinimized, VisibilityHidden:
 		w.call("webview_hide", "()V")
 	case VisibilityMaximized:
 		w.call("webview_run", "()V")
 	default:
 		w.call("webview_run", "()V")
 	}
+
+	w.SetFullscreen(v == VisibilityFullscreen)
+}
+
+func (w *webview) SetFullscreen(fullscreen bool) {
+	w.callArgs("webview_fullscreen", "(Z)V", func(env jni.Env) []jni.Value {
+		var enabled jni.Value
+		if fullscreen {
+			enabled = 1
+		}
+		return []jni.Value{enabled}
+	})
+}
+
+func (w *webview) OnFullscreenChanged(f func(fullscreen bool)) {
+	w.queue.Dispatch(func() {
+		w.onFullscreenChanged = append(w.onFullscreenChanged, f)
+	})
+}
+
+func (w *webview) Bounds() Rect {
+	s, err := w.callString("webview_bounds", "()Ljava/lang/String;")
+	if err != nil {
+		return Rect{}
+	}
+
+	v := parseFloats(s, 4)
+	return Rect{X: int64(v[0]), Y: int64(v[1]), Width: int64(v[2]), Height: int64(v[3])}
+}
+
+// SetBounds is ignored, the View is placed by the layout of the Activity.
+func (w *webview) SetBounds(bounds Rect) {
+	return
+}
+
+// Center is ignored, the View is placed by the layout of the Activity.
+func (w *webview) Center() {
+	return
+}
+
+func (w *webview) Focus(reason FocusReason) {
+	return
+}
+
+func (w *webview) OnFocusLeave(f func(reason FocusReason)) {
+	return
+}
+
+func (w *webview) SetMenu(menu *Menu) error {
+	return ErrFeatureNotSupported
+}
+
+func (w *webview) SetContextMenu(menu *Menu, mode ContextMenuMode) error {
+	return ErrFeatureNotSupported
+}
+
+func (w *webview) RegisterShortcut(chord string, f func()) error {
+	return ErrFeatureNotSupported
+}
+
+func (w *webview) SetIcon(icon image.Image) error {
+	return ErrFeatureNotSupported
+}
+
+func (w *webview) SetProgress(state ProgressState, value float64) {
+	return
+}
+
+func (w *webview) SetBadge(badge image.Image, description string) error {
+	return ErrFeatureNotSupported
+}
+
+func (w *webview) Screens() []Screen {
+	s, err := w.callString("webview_screen", "()Ljava/lang/String;")
+	if err != nil {
+		return nil
+	}
+
+	v := parseFloats(s, 3)
+	bounds := Rect{Width: int64(v[0]), Height: int64(v[1])}
+	return []Screen{{Bounds: bounds, WorkArea: bounds, Scale: v[2], Primary: true}}
+}
+
+func (w *webview) Scale() float64 {
+	s, err := w.callString("webview_screen", "()Ljava/lang/String;")
+	if err != nil {
+		return 1
+	}
+
+	if v := parseFloats(s, 3); v[2] > 0 {
+		return v[2]
+	}
+	return 1
+}
+
+// Zoom returns the text zoom multiplied by the pinch zoom.
+func (w *webview) Zoom() float64 {
+	percent, err := w.callInt("webview_get_zoom", "()I")
+	if err != nil || percent <= 0 {
+		return 1
+	}
+	return float64(percent) / 100
+}
+
+func (w *webview) SetZoom(factor float64) {
+	w.callArgs("webview_zoom", "(I)V", func(env jni.Env) []jni.Value {
+		return []jni.Value{
+			jni.Value(int(math.Round(factor * 100))),
+		}
+	})
+}
+
+// parseFloats parses the comma-separated values returned by Java, the missing values are zero.
+func parseFloats(s string, n int) []float64 {
+	v := make([]float64, n)
+	for i, p := range strings.SplitN(s, ",", n) {
+		v[i], _ = strconv.ParseFloat(p, 64)
+	}
+	return v
+}
+
+func (w *webview) ClearBrowsingData(ctx context.Context, kinds DataKinds, since time.Time) error {
+	if !since.IsZero() {
+		return ErrFeatureNotSupported
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		// The DataKinds values are the same of the `DATA_*` constants at gowebview_android.java.
+		done <- w.callArgs("webview_clear", "(I)V", func(env jni.Env) []jni.Value {
+			return []jni.Value{
+				jni.Value(kinds),
+			}
+		})
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (w *webview) setProxy(proxy *HTTPProxy) error {
@@ -187,7 +802,7 @@ func (w *webview) setProxy(proxy *HTTPProxy) error {
 	}
 
 	if !ok {
-		return errors.New("impossible to set proxy")
+		return ErrImpossibleProxy
 	}
 
 	return nil
@@ -214,7 +829,7 @@ func (w *webview) setCerts(certs []x509.Certificate) error {
 	}
 
 	if !ok {
-		return errors.New("impossible to set certs")
+		return ErrImpossibleCertificates
 	}
 
 	return nil
@@ -227,21 +842,60 @@ func (w *webview) call(name, sig string) (err error) {
 }
 
 func (w *webview) callArgs(name, sig string, args func(env jni.Env) []jni.Value) (err error) {
-	w.mutex.Lock()
-	defer w.mutex.Unlock()
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
 
 	if w.objWebView == 0 || w.clsWebView == 0 {
 		return
 	}
 
 	return jni.Do(w.vm, func(env jni.Env) error {
-		return jni.CallVoidMethod(env, w.objWebView, jni.GetMethodID(env, w.clsWebView, name, sig), args(env)...)
+		return javaException(jni.CallVoidMethod(env, w.objWebView, jni.GetMethodID(env, w.clsWebView, name, sig), args(env)...))
 	})
 }
 
+func (w *webview) callString(name, sig string) (s string, err error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.objWebView == 0 || w.clsWebView == 0 {
+		return
+	}
+
+	err = jni.Do(w.vm, func(env jni.Env) error {
+		o, err := jni.CallObjectMethod(env, w.objWebView, jni.GetMethodID(env, w.clsWebView, name, sig))
+		if err != nil {
+			return javaException(err)
+		}
+		defer jni.DeleteLocalRef(env, o)
+
+		s = jni.GoString(env, jni.String(o))
+		return nil
+	})
+
+	return s, err
+}
+
+func (w *webview) callInt(name, sig string) (i int, err error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.objWebView == 0 || w.clsWebView == 0 {
+		return
+	}
+
+	err = jni.Do(w.vm, func(env jni.Env) error {
+		v, err := jni.CallIntMethod(env, w.objWebView, jni.GetMethodID(env, w.clsWebView, name, sig))
+		i = int(v)
+		return javaException(err)
+	})
+
+	return i, err
+}
+
 func (w *webview) callBooleanArgs(name, sig string, args func(env jni.Env) []jni.Value) (b bool, err error) {
-	w.mutex.Lock()
-	defer w.mutex.Unlock()
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
 
 	if w.objWebView == 0 || w.clsWebView == 0 {
 		return
@@ -249,7 +903,7 @@ func (w *webview) callBooleanArgs(name, sig string, args func(env jni.Env) []jni
 
 	err = jni.Do(w.vm, func(env jni.Env) error {
 		b, err = jni.CallBooleanMethod(env, w.objWebView, jni.GetMethodID(env, w.clsWebView, name, sig), args(env)...)
-		return err
+		return javaException(err)
 	})
 
 	return b, err
@@ -9,6 +9,9 @@ import android.view.KeyEvent;
 import android.webkit.WebSettings;
 import android.content.Context;
 import android.webkit.WebViewClient;
+import android.webkit.WebChromeClient;
+import android.util.DisplayMetrics;
+import java.util.concurrent.atomic.AtomicReference;
 import android.widget.Toast;
 import android.webkit.WebView;
 import android.util.Log;
@@ -34,11 +37,86 @@ import android.os.Build.VERSION_CODES;
 import android.util.Base64;
 import android.webkit.URLUtil;
 import android.webkit.WebResourceRequest;
+import android.webkit.CookieManager;
+import android.webkit.WebStorage;
+import android.webkit.RenderProcessGoneDetail;
+import java.util.concurrent.LinkedBlockingQueue;
+import android.app.Fragment;
+import android.content.ActivityNotFoundException;
+import android.content.ClipData;
+import android.net.Uri;
+import android.text.TextUtils;
+import android.webkit.ValueCallback;
+import android.app.AlertDialog;
+import android.content.DialogInterface;
+import android.app.Notification;
+import android.app.NotificationChannel;
+import android.app.NotificationManager;
+import android.app.PendingIntent;
+import android.content.ClipboardManager;
+import android.os.Handler;
+import android.os.Looper;
+import java.util.concurrent.atomic.AtomicBoolean;
 
 public class gowebview_android {
     private View primaryView;
     private WebView webBrowser;
     private PublicKey[] additionalCerts;
+    private String lastUrl = "";
+    private volatile int textZoom = 100;
+    private volatile float pinchZoom = 1;
+    private volatile boolean zoomDisabled = false;
+
+    // The customView is the fullscreen element of the page, such as a video. They are only used on the UI thread.
+    private View customView;
+    private WebChromeClient.CustomViewCallback customViewCallback;
+    private boolean fullscreen = false;
+    private boolean fullscreenByElement = false;
+
+    // Events are consumed by Go using `webview_event`, formatted as "name" or "name:argument".
+    private final LinkedBlockingQueue<String> events = new LinkedBlockingQueue<String>();
+
+    // The functions of `Dispatch` are drained on the UI thread, one drain is posted until it runs.
+    private final Handler mainHandler = new Handler(Looper.getMainLooper());
+    private final AtomicBoolean drainPosted = new AtomicBoolean(false);
+    private volatile int uiThread;
+
+    // Implemented by Go, it runs the functions of `Dispatch` of the given queue.
+    private static native void webview_drain(long handle);
+    private static final String EVENT_CLOSED = "closed";
+    private static final String EVENT_PROCESS_FAILED = "process_failed";
+    private static final String EVENT_CLOSE_REQUESTED = "close_requested";
+    private static final String EVENT_ZOOM_CHANGED = "zoom_changed";
+    private static final String EVENT_FULLSCREEN_CHANGED = "fullscreen_changed";
+    private static final String EVENT_FILE_CHOOSER = "file_chooser";
+    private static final String EVENT_FILES_CHOSEN = "files_chosen";
+    private static final String EVENT_MESSAGE_BOX = "message_box";
+
+    // The buttons of the message box, they must match the `Buttons` and `Button` of Go.
+    private static final int BUTTONS_OK = 0;
+    private static final int BUTTONS_OK_CANCEL = 1;
+    private static final int BUTTONS_YES_NO = 2;
+    private static final int BUTTONS_YES_NO_CANCEL = 3;
+    private static final int BUTTONS_RETRY_CANCEL = 4;
+    private static final int BUTTON_OK = 1;
+    private static final int BUTTON_CANCEL = 2;
+    private static final int BUTTON_YES = 3;
+    private static final int BUTTON_NO = 4;
+    private static final int BUTTON_RETRY = 5;
+
+    private static final String NOTIFICATION_CHANNEL = "gowebview";
+    private static int notificationID = 0;
+
+    // The callback of the <input type="file">, which is waiting for the files.
+    private ValueCallback<Uri[]> fileCallback;
+
+    // Same values of `DataKinds` at profile.go
+    private static final int DATA_COOKIES = 1 << 0;
+    private static final int DATA_CACHE = 1 << 1;
+    private static final int DATA_LOCAL_STORAGE = 1 << 2;
+    private static final int DATA_INDEXEDDB = 1 << 3;
+    private static final int DATA_SERVICE_WORKERS = 1 << 4;
+    private static final int DATA_HISTORY = 1 << 5;
 
     public class gowebview_boolean {
         private boolean b;
@@ -47,6 +125,26 @@ public class gowebview_android {
     }
 
     public class gowebview_webbrowser extends WebViewClient {
+        @Override public void doUpdateVisitedHistory(WebView v, String url, boolean isReload) {
+            lastUrl = url;
+        }
+
+        // The scale includes the density of the screen, the pinch zoom is relative to it.
+        @Override public void onScaleChanged(WebView v, float oldScale, float newScale) {
+            float zoom = newScale / v.getContext().getResources().getDisplayMetrics().density;
+            if (Math.abs(zoom - pinchZoom) < 0.001f) {
+                return;
+            }
+            pinchZoom = zoom;
+            events.offer(EVENT_ZOOM_CHANGED + ":" + webview_get_zoom());
+        }
+
+        // The WebView can't be used after the render process is gone, it must be replaced by `webview_recover`.
+        @Override public boolean onRenderProcessGone(WebView v, RenderProcessGoneDetail detail) {
+            events.offer(EVENT_PROCESS_FAILED + ":" + (detail.didCrash() ? "crash" : "killed"));
+            return true;
+        }
+
         @Override public boolean shouldOverrideUrlLoading(WebView v, WebResourceRequest request) {
             String url = request.getUrl().toString();
             if (url.isEmpty()) {
@@ -100,6 +198,325 @@ public class gowebview_android {
         }
     }
 
+    public class gowebview_chrome extends WebChromeClient {
+        // Executed when the page calls `window.close()`, the WebView is closed by Go if it's allowed.
+        @Override public void onCloseWindow(WebView v) {
+            events.offer(EVENT_CLOSE_REQUESTED);
+        }
+
+        // Executed when the page requests the fullscreen, the element is displayed instead of the WebView.
+        @Override public void onShowCustomView(View view, WebChromeClient.CustomViewCallback callback) {
+            if (customView != null) {
+                callback.onCustomViewHidden();
+                return;
+            }
+
+            customView = view;
+            customViewCallback = callback;
+            ((Activity)primaryView.getContext()).setContentView(customView);
+
+            fullscreenByElement = !fullscreen;
+            setFullscreen(true);
+        }
+
+        @Override public void onHideCustomView() {
+            hideCustomView();
+        }
+
+        // Executed when the page opens the <input type="file">, the accepted types are converted by Go, which calls
+        // `webview_choose` with the request zero.
+        @Override public boolean onShowFileChooser(WebView v, ValueCallback<Uri[]> callback, FileChooserParams params) {
+            if (fileCallback != null) {
+                fileCallback.onReceiveValue(null);
+            }
+            fileCallback = callback;
+
+            boolean multiple = params.getMode() == FileChooserParams.MODE_OPEN_MULTIPLE;
+            events.offer(EVENT_FILE_CHOOSER + ":" + multiple + ":" + TextUtils.join(",", params.getAcceptTypes()));
+            return true;
+        }
+    }
+
+    // The headless fragment receives the result of the document picker, since the Activity belongs to the application.
+    public static class gowebview_chooser extends Fragment {
+        private ValueCallback<Uri[]> result;
+
+        public gowebview_chooser() {}
+
+        @Override public void onActivityResult(int requestCode, int resultCode, Intent data) {
+            Uri[] uris = null;
+            if (resultCode == Activity.RESULT_OK && data != null) {
+                ClipData clip = data.getClipData();
+                if (clip != null) {
+                    uris = new Uri[clip.getItemCount()];
+                    for (int i = 0; i < uris.length; i++) {
+                        uris[i] = clip.getItemAt(i).getUri();
+                    }
+                } else if (data.getData() != null) {
+                    uris = new Uri[]{data.getData()};
+                }
+            }
+
+            getFragmentManager().beginTransaction().remove(this).commit();
+
+            // The result is lost if the Activity was recreated.
+            if (result != null) {
+                result.onReceiveValue(uris);
+            }
+        }
+    }
+
+    // It must be called from the UI thread, the result is null if the user cancels the picker.
+    private void choose(Intent intent, ValueCallback<Uri[]> result) {
+        Activity activity = (Activity)primaryView.getContext();
+
+        gowebview_chooser chooser = new gowebview_chooser();
+        chooser.result = result;
+        activity.getFragmentManager().beginTransaction().add(chooser, "gowebview_chooser").commit();
+        activity.getFragmentManager().executePendingTransactions();
+
+        try {
+            chooser.startActivityForResult(intent, 1);
+        } catch (ActivityNotFoundException e) {
+            activity.getFragmentManager().beginTransaction().remove(chooser).commit();
+            result.onReceiveValue(null);
+        }
+    }
+
+    // Executed by Go, it displays the document picker. The MIME types are separated by comma, the empty string allows
+    // all files. The request zero answers the <input type="file">, other requests are answered by Go, using the
+    // EVENT_FILES_CHOSEN with the URIs separated by new lines.
+    public void webview_choose(final int request, final String mimes, final boolean multiple, final boolean save, final String filename) {
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                String[] types = mimes.isEmpty() ? new String[0] : mimes.split(",");
+
+                Intent intent = new Intent(save ? Intent.ACTION_CREATE_DOCUMENT : Intent.ACTION_OPEN_DOCUMENT);
+                intent.addCategory(Intent.CATEGORY_OPENABLE);
+                intent.setType(types.length == 1 ? types[0] : "*/*");
+                if (types.length > 1) {
+                    intent.putExtra(Intent.EXTRA_MIME_TYPES, types);
+                }
+                if (save) {
+                    intent.putExtra(Intent.EXTRA_TITLE, filename);
+                } else {
+                    intent.putExtra(Intent.EXTRA_ALLOW_MULTIPLE, multiple);
+                }
+
+                choose(intent, new ValueCallback<Uri[]>() {
+                    public void onReceiveValue(Uri[] uris) {
+                        if (request == 0) {
+                            if (fileCallback != null) {
+                                fileCallback.onReceiveValue(uris);
+                                fileCallback = null;
+                            }
+                            return;
+                        }
+
+                        StringBuilder event = new StringBuilder(EVENT_FILES_CHOSEN + ":" + request + ":");
+                        for (int i = 0; uris != null && i < uris.length; i++) {
+                            if (i > 0) {
+                                event.append('\n');
+                            }
+                            event.append(uris[i].toString());
+                        }
+                        events.offer(event.toString());
+                    }
+                });
+            }
+        });
+    }
+
+    // Executed when call `.MessageBox()`, the clicked button is answered by EVENT_MESSAGE_BOX. Dismissing the message
+    // box is the same as clicking the cancel button, or the OK button if that is the only one.
+    public void webview_message_box(final int request, final String title, final String text, final int buttons) {
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                final AtomicReference<Integer> answered = new AtomicReference<Integer>(null);
+                final int dismissed = buttons == BUTTONS_OK ? BUTTON_OK : BUTTON_CANCEL;
+
+                AlertDialog.Builder builder = new AlertDialog.Builder(primaryView.getContext());
+                builder.setTitle(title);
+                builder.setMessage(text);
+                builder.setCancelable(buttons != BUTTONS_YES_NO);
+
+                DialogInterface.OnClickListener listener = new DialogInterface.OnClickListener() {
+                    public void onClick(DialogInterface dialog, int which) {
+                        switch (which) {
+                        case DialogInterface.BUTTON_POSITIVE:
+                            answered.set(buttons == BUTTONS_YES_NO || buttons == BUTTONS_YES_NO_CANCEL ? BUTTON_YES : buttons == BUTTONS_RETRY_CANCEL ? BUTTON_RETRY : BUTTON_OK);
+                            break;
+                        case DialogInterface.BUTTON_NEGATIVE:
+                            answered.set(buttons == BUTTONS_YES_NO || buttons == BUTTONS_YES_NO_CANCEL ? BUTTON_NO : BUTTON_CANCEL);
+                            break;
+                        default:
+                            answered.set(BUTTON_CANCEL);
+                        }
+                    }
+                };
+
+                switch (buttons) {
+                case BUTTONS_OK:
+                    builder.setPositiveButton(android.R.string.ok, listener);
+                    break;
+                case BUTTONS_OK_CANCEL:
+                    builder.setPositiveButton(android.R.string.ok, listener);
+                    builder.setNegativeButton(android.R.string.cancel, listener);
+                    break;
+                case BUTTONS_YES_NO:
+                    builder.setPositiveButton(android.R.string.yes, listener);
+                    builder.setNegativeButton(android.R.string.no, listener);
+                    break;
+                case BUTTONS_YES_NO_CANCEL:
+                    builder.setPositiveButton(android.R.string.yes, listener);
+                    builder.setNegativeButton(android.R.string.no, listener);
+                    builder.setNeutralButton(android.R.string.cancel, listener);
+                    break;
+                case BUTTONS_RETRY_CANCEL:
+                    builder.setPositiveButton("Retry", listener);
+                    builder.setNegativeButton(android.R.string.cancel, listener);
+                    break;
+                }
+
+                builder.setOnDismissListener(new DialogInterface.OnDismissListener() {
+                    public void onDismiss(DialogInterface dialog) {
+                        Integer button = answered.get();
+                        events.offer(EVENT_MESSAGE_BOX + ":" + request + ":" + (button == null ? dismissed : button));
+                    }
+                });
+                builder.show();
+            }
+        });
+    }
+
+    // Executed when call `.Notify()`, clicking the notification opens the application. It needs the
+    // POST_NOTIFICATIONS permission, otherwise the notification is silently ignored by the system.
+    public void webview_notify(final String title, final String text) {
+        Context context = primaryView.getContext();
+        NotificationManager manager = (NotificationManager)context.getSystemService(Context.NOTIFICATION_SERVICE);
+
+        Notification.Builder builder;
+        if (Build.VERSION.SDK_INT >= Build.VERSION_CODES.O) {
+            manager.createNotificationChannel(new NotificationChannel(NOTIFICATION_CHANNEL, "Notifications", NotificationManager.IMPORTANCE_DEFAULT));
+            builder = new Notification.Builder(context, NOTIFICATION_CHANNEL);
+        } else {
+            builder = new Notification.Builder(context);
+        }
+
+        builder.setContentTitle(title);
+        builder.setContentText(text);
+        builder.setSmallIcon(context.getApplicationInfo().icon);
+        builder.setAutoCancel(true);
+
+        Intent intent = context.getPackageManager().getLaunchIntentForPackage(context.getPackageName());
+        if (intent != null) {
+            int flags = PendingIntent.FLAG_UPDATE_CURRENT;
+            if (Build.VERSION.SDK_INT >= Build.VERSION_CODES.M) {
+                flags |= PendingIntent.FLAG_IMMUTABLE;
+            }
+            builder.setContentIntent(PendingIntent.getActivity(context, 0, intent, flags));
+        }
+
+        synchronized (gowebview_android.class) {
+            notificationID++;
+            manager.notify(notificationID, builder.build());
+        }
+    }
+
+    // Executed when call `.Clipboard().ReadText()`, it returns the empty string if the clipboard doesn't have text.
+    // The ClipboardManager needs a Looper on older versions, so it runs on the UI thread.
+    public String webview_clipboard_read() {
+        final Semaphore mutex = new Semaphore(0);
+        final AtomicReference<String> result = new AtomicReference<String>("");
+
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                ClipboardManager manager = (ClipboardManager)primaryView.getContext().getSystemService(Context.CLIPBOARD_SERVICE);
+                ClipData clip = manager.getPrimaryClip();
+                if (clip != null && clip.getItemCount() > 0) {
+                    CharSequence text = clip.getItemAt(0).coerceToText(primaryView.getContext());
+                    if (text != null) {
+                        result.set(text.toString());
+                    }
+                }
+
+                mutex.release();
+            }
+        });
+
+        try {
+            mutex.acquire();
+        } catch (InterruptedException e) {
+            e.printStackTrace();
+        }
+
+        return result.get();
+    }
+
+    // Executed when call `.Clipboard().WriteText()`.
+    public void webview_clipboard_write(final String text) {
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                ClipboardManager manager = (ClipboardManager)primaryView.getContext().getSystemService(Context.CLIPBOARD_SERVICE);
+                manager.setPrimaryClip(ClipData.newPlainText("text", text));
+            }
+        });
+    }
+
+    // It must be called from the UI thread, it only leaves the fullscreen if it was entered by the page.
+    private void hideCustomView() {
+        if (customView == null) {
+            return;
+        }
+
+        ((Activity)primaryView.getContext()).setContentView(webBrowser);
+        customViewCallback.onCustomViewHidden();
+        customView = null;
+        customViewCallback = null;
+
+        if (fullscreenByElement) {
+            setFullscreen(false);
+        }
+        fullscreenByElement = false;
+    }
+
+    // It must be called from the UI thread, it hides the system bars when enabled.
+    private void setFullscreen(boolean enabled) {
+        if (fullscreen == enabled) {
+            return;
+        }
+        fullscreen = enabled;
+
+        View decor = ((Activity)primaryView.getContext()).getWindow().getDecorView();
+        if (enabled) {
+            decor.setSystemUiVisibility(View.SYSTEM_UI_FLAG_FULLSCREEN | View.SYSTEM_UI_FLAG_HIDE_NAVIGATION | View.SYSTEM_UI_FLAG_IMMERSIVE_STICKY | View.SYSTEM_UI_FLAG_LAYOUT_STABLE | View.SYSTEM_UI_FLAG_LAYOUT_FULLSCREEN | View.SYSTEM_UI_FLAG_LAYOUT_HIDE_NAVIGATION);
+        } else {
+            decor.setSystemUiVisibility(View.SYSTEM_UI_FLAG_VISIBLE);
+        }
+
+        events.offer(EVENT_FULLSCREEN_CHANGED + ":" + enabled);
+    }
+
+    // Executed by Go, it returns the thread id of the UI thread, used to know if `DispatchSync` must run immediately.
+    public int webview_ui_thread() {
+        return uiThread;
+    }
+
+    // Executed by Go when a function is dispatched, the functions run later on the UI thread, even when it's called
+    // from the UI thread.
+    public void webview_wake(final long handle) {
+        if (!drainPosted.compareAndSet(false, true)) {
+            return;
+        }
+
+        mainHandler.post(new Runnable() {
+            public void run() {
+                drainPosted.set(false);
+                webview_drain(handle);
+            }
+        });
+    }
+
     // Executed when call `New(config *Config)`
     public void webview_create(View v) {
         primaryView = v;
@@ -108,19 +525,8 @@ public class gowebview_android {
 
         ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
             public void run() {
-                webBrowser = new WebView(primaryView.getContext());
-                WebSettings webSettings = webBrowser.getSettings();
-                webSettings.setJavaScriptEnabled(true);
-                if (android.os.Build.VERSION.SDK_INT >= android.os.Build.VERSION_CODES.O_MR1) {
-                    webSettings.setSafeBrowsingEnabled(false);
-                }
-                webSettings.setMixedContentMode(WebSettings.MIXED_CONTENT_COMPATIBILITY_MODE);
-                webSettings.setUseWideViewPort(true);
-                webSettings.setLoadWithOverviewMode(true);
-                webSettings.setDomStorageEnabled(true);
-                webSettings.setDatabaseEnabled(true);
-
-                webBrowser.setWebViewClient(new gowebview_webbrowser());
+                uiThread = android.os.Process.myTid();
+                webBrowser = newWebBrowser();
 
                 mutex.release();
             }
@@ -133,6 +539,58 @@ public class gowebview_android {
         }
     }
 
+    private WebView newWebBrowser() {
+        WebView browser = new WebView(primaryView.getContext());
+        WebSettings webSettings = browser.getSettings();
+        webSettings.setJavaScriptEnabled(true);
+        if (android.os.Build.VERSION.SDK_INT >= android.os.Build.VERSION_CODES.O_MR1) {
+            webSettings.setSafeBrowsingEnabled(false);
+        }
+        webSettings.setMixedContentMode(WebSettings.MIXED_CONTENT_COMPATIBILITY_MODE);
+        webSettings.setUseWideViewPort(true);
+        webSettings.setLoadWithOverviewMode(true);
+        webSettings.setDomStorageEnabled(true);
+        webSettings.setDatabaseEnabled(true);
+        webSettings.setTextZoom(textZoom);
+        webSettings.setSupportZoom(!zoomDisabled);
+        webSettings.setBuiltInZoomControls(!zoomDisabled);
+        webSettings.setDisplayZoomControls(false);
+
+        browser.setWebViewClient(new gowebview_webbrowser());
+        browser.setWebChromeClient(new gowebview_chrome());
+        return browser;
+    }
+
+    // Executed by Go, it blocks until the next event.
+    public String webview_event() {
+        try {
+            return events.take();
+        } catch (InterruptedException e) {
+            return EVENT_CLOSED;
+        }
+    }
+
+    // Executed when the `Config.AutoRecover` is defined, it replaces the WebView after the render process is gone and
+    // reloads the last URL.
+    public void webview_recover() {
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                WebView previous = webBrowser;
+                boolean visible = previous.getParent() != null;
+
+                webBrowser = newWebBrowser();
+                if (visible) {
+                    ((Activity)primaryView.getContext()).setContentView(webBrowser);
+                }
+                previous.destroy();
+
+                if (!lastUrl.isEmpty()) {
+                    webBrowser.loadUrl(lastUrl);
+                }
+            }
+        });
+    }
+
     // Executed when call `.SetURL(url string)`
     public void webview_navigate(String url) {
         ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
@@ -151,8 +609,12 @@ public class gowebview_android {
         });
     }
 
-    // Executed when call `.Destroy()`
+    // Executed when call `.Destroy()`, it waits until the WebView is destroyed.
     public void webview_destroy() {
+        final Semaphore mutex = new Semaphore(0);
+
+        events.offer(EVENT_CLOSED);
+
         ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
             public void run() {
                 ((Activity)primaryView.getContext()).setContentView(primaryView);
@@ -161,8 +623,16 @@ public class gowebview_android {
                 webBrowser.removeAllViews();
                 webBrowser.pauseTimers();
                 webBrowser.destroy();
+
+                mutex.release();
             }
         });
+
+        try {
+            mutex.acquire();
+        } catch (InterruptedException e) {
+            e.printStackTrace();
+        }
     }
 
     // Executed when call `.SetVisibility()`
@@ -174,6 +644,118 @@ public class gowebview_android {
         });
     }
 
+    // Executed when call `.Bounds()`, formatted as "x,y,width,height".
+    public String webview_bounds() {
+        final Semaphore mutex = new Semaphore(0);
+        final AtomicReference<String> result = new AtomicReference<String>("0,0,0,0");
+
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                int[] location = new int[2];
+                webBrowser.getLocationOnScreen(location);
+                result.set(location[0] + "," + location[1] + "," + webBrowser.getWidth() + "," + webBrowser.getHeight());
+
+                mutex.release();
+            }
+        });
+
+        try {
+            mutex.acquire();
+        } catch (InterruptedException e) {
+            e.printStackTrace();
+        }
+
+        return result.get();
+    }
+
+    // Executed when call `.Screens()`, formatted as "width,height,density".
+    public String webview_screen() {
+        DisplayMetrics metrics = primaryView.getContext().getResources().getDisplayMetrics();
+        return metrics.widthPixels + "," + metrics.heightPixels + "," + metrics.density;
+    }
+
+    // Executed when call `.SetFullscreen()` or `.SetVisibility()`, leaving the fullscreen also closes the fullscreen
+    // element of the page.
+    public void webview_fullscreen(final boolean enabled) {
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                if (!enabled && customView != null) {
+                    fullscreenByElement = true;
+                    hideCustomView();
+                    return;
+                }
+
+                fullscreenByElement = false;
+                setFullscreen(enabled);
+            }
+        });
+    }
+
+    // Executed when call `.SetZoom()`, the zoom is in percent.
+    public void webview_zoom(final int percent) {
+        if (textZoom == percent) {
+            return;
+        }
+        textZoom = percent;
+
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                webBrowser.getSettings().setTextZoom(percent);
+            }
+        });
+
+        events.offer(EVENT_ZOOM_CHANGED + ":" + webview_get_zoom());
+    }
+
+    // Executed when call `.Zoom()`, the zoom is in percent, the text zoom multiplied by the pinch zoom.
+    public int webview_get_zoom() {
+        return Math.round(textZoom * pinchZoom);
+    }
+
+    // Executed when `WindowConfig.DisableZoom` is true, it prevents the pinch zoom.
+    public void webview_disable_zoom() {
+        zoomDisabled = true;
+
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                webBrowser.getSettings().setSupportZoom(false);
+                webBrowser.getSettings().setBuiltInZoomControls(false);
+            }
+        });
+    }
+
+    // Executed when call `.ClearBrowsingData()`
+    public void webview_clear(final int kinds) {
+        final Semaphore mutex = new Semaphore(0);
+
+        ((Activity)primaryView.getContext()).runOnUiThread(new Runnable() {
+            public void run() {
+                if ((kinds & DATA_COOKIES) != 0) {
+                    CookieManager.getInstance().removeAllCookies(null);
+                    CookieManager.getInstance().flush();
+                }
+                if ((kinds & DATA_CACHE) != 0) {
+                    webBrowser.clearCache(true);
+                }
+                // WebStorage doesn't distinguish between localStorage, IndexedDB and service workers.
+                if ((kinds & (DATA_LOCAL_STORAGE | DATA_INDEXEDDB | DATA_SERVICE_WORKERS)) != 0) {
+                    WebStorage.getInstance().deleteAllData();
+                }
+                if ((kinds & DATA_HISTORY) != 0) {
+                    webBrowser.clearHistory();
+                }
+
+                mutex.release();
+            }
+        });
+
+        try {
+            mutex.acquire();
+        } catch (InterruptedException e) {
+            e.printStackTrace();
+        }
+    }
+
     public boolean webview_proxy(String host, String port) {
         final Semaphore mutex = new Semaphore(0);
         final gowebview_boolean result = new gowebview_boolean();
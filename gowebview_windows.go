@@ -3,36 +3,68 @@
 package gowebview
 
 import (
-	"crypto/sha256"
-	"crypto/x509"
-	"encoding/base64"
+	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
-	"github.com/inkeliz/gowebview/internal/network"
+	"github.com/inkeliz/gowebview/internal/accel"
+	"github.com/inkeliz/gowebview/internal/closing"
+	"github.com/inkeliz/gowebview/internal/menu"
+	"github.com/inkeliz/gowebview/internal/recovery"
 	"github.com/inkeliz/gowebview/internal/wincom"
 	"github.com/inkeliz/w32"
 	"golang.org/x/sys/windows"
+	"math"
+	"net/url"
 	"os"
-	"path/filepath"
-	"runtime"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
 type webview struct {
-	dll     *windows.Proc
-	browser browser
-	view    view
-	config  *Config
-
-	done  chan bool
-	queue chan func()
+	thread     *thread
+	standalone bool
+	browser    browser
+	view       view
+	config     *Config
+	release    func()
+	closed     func()
+
+	url             string
+	backoff         *recovery.Backoff
+	onProcessFailed []func(failure ProcessFailure)
+	onClose         closing.Handlers
+	onZoomChanged   []func(factor float64)
+
+	onFullscreenChanged []func(fullscreen bool)
+	onFocusLeave        []func(reason FocusReason)
+
+	shortcuts *accel.Table
+
+	// notifications is true if the Notifications API is granted, see SetNotificationsAllowed. The notification is
+	// the id of the notification of the page which is displayed by the notificationTray, if any.
+	notifications    bool
+	notification     int
+	notificationTray *tray
+
+	ready  chan error
+	done   chan bool
+	reason error
 }
 
 type browser struct {
-	controller *wincom.ICoreWebView2Controller
-	webview    *wincom.ICoreWebView2
+	environment *wincom.ICoreWebView2Environment
+	controller  *wincom.ICoreWebView2Controller
+	webview     *wincom.ICoreWebView2
+
+	// events removes the event handlers, when the browser is destroyed.
+	events []func()
+
+	// contextMenuRequested is true if the ContextMenuRequested is handled, see addContextMenuRequested.
+	contextMenuRequested bool
+	customItemSelected   *customItemSelectedHandler
 }
 
 type view struct {
@@ -41,155 +73,520 @@ type view struct {
 	icon     w32.HICON
 	window   w32.HWND
 
-	min Point
-	max Point
+	// parent is the WindowConfig.Window, the window is a child of it. The hook watches the moves of the parent.
+	parent w32.HWND
+	hook   uintptr
+
+	// min and max are in logical units, scaled by the scale of the current monitor.
+	min   Point
+	max   Point
+	scale float64
+
+	// fullscreen is true when the window covers the monitor, the placement and style are restored when it leaves.
+	// The byElement is true when the fullscreen is requested by the page, so it leaves when the page exits.
+	fullscreen bool
+	byElement  bool
+	placement  w32.WINDOWPLACEMENT
+	style      int32
+
+	// iconData is the ICO of SetIcon, the icons are recreated when the scale changes.
+	iconData  []byte
+	smallIcon w32.HICON
+	bigIcon   w32.HICON
+
+	progress         ProgressState
+	progressValue    float64
+	badge            w32.HICON
+	badgeDescription string
+
+	menu        *menu.Tree
+	hmenu       w32.HMENU
+	contextMenu *menu.Tree
+
+	// contextMenuExtend is true if the ContextMenuExtend is used, the contextMenuItems are appended to the context
+	// menu of the browser, when supported. The contextMenuCommands are the items of the last context menu, by the
+	// command id.
+	contextMenuExtend   bool
+	contextMenuItems    *menu.Tree
+	contextMenuCommands map[int32]*menu.Item
 }
 
 func newWindow(config *Config) (wv WebView, err error) {
-	w := &webview{
-		config: config,
-		done:   make(chan bool, 1),
-		queue:  make(chan func(), 1<<16),
+	t, err := newThread(config)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = extract(config.WindowConfig.Path); err != nil {
+	// The thread is used only by this window, so it's stopped when the window is closed.
+	w, err := t.newWebview(config, t.quit)
+	if err != nil {
+		t.quit()
 		return nil, err
 	}
+	w.standalone = true
 
-	if config.TransportConfig.IgnoreNetworkIsolation && !network.IsAllowedPrivateConnections() {
-		if err := network.EnablePrivateConnections(); err != nil {
-			return nil, err
-		}
-	}
+	return w, nil
+}
 
-	for _, s := range []string{"WEBVIEW2_BROWSER_EXECUTABLE_FOLDER", "WEBVIEW2_USER_DATA_FOLDER", "WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS", "WEBVIEW2_RELEASE_CHANNEL_PREFERENCE"} {
-		os.Unsetenv(s)
+func (t *thread) newWebview(config *Config, closed func()) (w *webview, err error) {
+	w = &webview{
+		thread: t,
+		config: config,
+		closed: closed,
+		ready:  make(chan error, 1),
+		done:   make(chan bool),
+
+		shortcuts:     newShortcuts(config.WindowConfig.DisableAccelerators),
+		notifications: config.WindowConfig.AllowNotifications,
 	}
 
-	w.setProxy(config.TransportConfig.Proxy)
-	w.setCerts(config.TransportConfig.CertificateAuthorities)
+	if config.AutoRecover != nil {
+		w.backoff = recovery.New(recovery.Policy(*config.AutoRecover))
+	}
 
-	dll, err := windows.LoadDLL(filepath.Join(config.WindowConfig.Path, "WebView2Loader.dll"))
-	if err != nil {
+	if w.release, err = config.Profile.acquire(); err != nil {
 		return nil, err
 	}
 
-	w.dll, err = dll.FindProc("CreateCoreWebView2EnvironmentWithOptions")
-	if err != nil {
-		return nil, err
+	ok := t.dispatch(func() {
+		if err := w.createWindow(); err != nil {
+			w.release()
+			w.ready <- err
+			return
+		}
+
+		watchlist.Store(w.view.window, w)
+		t.views[w] = true
+
+		// The WM_NCCALCSIZE is sent before the window is in the watchlist, so the frame is recalculated.
+		if w.config.WindowConfig.Style.Has(StyleFrameless) {
+			w32.SetWindowPos(w.view.window, 0, 0, 0, 0, 0, w32.SWP_NOMOVE|w32.SWP_NOSIZE|w32.SWP_NOZORDER|w32.SWP_NOACTIVATE|w32.SWP_FRAMECHANGED)
+		}
+
+		w.createBrowser(func(err error) {
+			w.ready <- err
+		})
+	})
+	if !ok {
+		w.release()
+		return nil, ErrClosed
 	}
 
-	if err = w.create(); err != nil {
-		return nil, err
+	// The pending functions are dropped once the queue is closed, when the thread ends.
+	var ready error
+	select {
+	case ready = <-w.ready:
+	case <-t.queue.Done():
+		w.release()
+		return nil, ErrClosed
+	}
+	if ready != nil {
+		t.dispatch(w.destroy)
+		return nil, ready
 	}
 
-	w.SetSize(w.config.WindowConfig.Size, HintNone)
+	w.updateSize(false)
 	w.SetURL(w.config.URL)
 	w.SetTitle(w.config.WindowConfig.Title)
 
 	return w, nil
 }
 
-func (w *webview) setProxy(proxy *HTTPProxy) {
-	if proxy == nil || (proxy.IP == "" && proxy.Port == "") {
+// createBrowser creates the browser inside the window, the function is called once the browser is ready. It must be
+// called from the UI thread.
+func (w *webview) createBrowser(completed func(err error)) {
+	w.thread.withEnvironment(func(environment *wincom.ICoreWebView2Environment, err error) {
+		if err != nil {
+			completed(err)
+			return
+		}
+
+		h := &controllerHandler{webview: w, environment: environment, completed: completed}
+		h.VTBL = controllerHandlerVTBL
+		pending.Store(h, true)
+
+		res, _, _ := syscall.Syscall(environment.VTBL.CreateCoreWebView2Controller, 3, uintptr(unsafe.Pointer(environment)), uintptr(w.view.window), uintptr(unsafe.Pointer(h)))
+		if res != 0 {
+			pending.Delete(h)
+			completed(hresult("CreateCoreWebView2Controller", res))
+		}
+	})
+}
+
+func (w *webview) controllerCompleted(environment *wincom.ICoreWebView2Environment, createdController *wincom.ICoreWebView2Controller) {
+	syscall.Syscall(createdController.VTBL.AddRef, 1, uintptr(unsafe.Pointer(createdController)), 0, 0)
+	w.browser.environment = environment
+	w.browser.controller = createdController
+
+	createdWebView2 := new(wincom.ICoreWebView2)
+
+	syscall.Syscall(createdController.VTBL.GetCoreWebView2, 2, uintptr(unsafe.Pointer(createdController)), uintptr(unsafe.Pointer(&createdWebView2)), 0)
+	w.browser.webview = createdWebView2
+
+	syscall.Syscall(w.browser.webview.VTBL.AddRef, 1, uintptr(unsafe.Pointer(w.browser.webview)), 0, 0)
+
+	processFailed := &processFailedHandler{webview: w}
+	processFailed.VTBL = processFailedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.webview), w.browser.webview.VTBL.AddProcessFailed, w.browser.webview.VTBL.RemoveProcessFailed, unsafe.Pointer(processFailed))
+
+	closeRequested := &closeRequestedHandler{webview: w}
+	closeRequested.VTBL = closeRequestedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.webview), w.browser.webview.VTBL.AddWindowCloseRequested, w.browser.webview.VTBL.RemoveWindowCloseRequested, unsafe.Pointer(closeRequested))
+
+	zoomChanged := &zoomChangedHandler{webview: w}
+	zoomChanged.VTBL = zoomChangedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.controller), w.browser.controller.VTBL.AddZoomFactorChanged, w.browser.controller.VTBL.RemoveZoomFactorChanged, unsafe.Pointer(zoomChanged))
+
+	messageReceived := &messageReceivedHandler{webview: w}
+	messageReceived.VTBL = messageReceivedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.webview), w.browser.webview.VTBL.AddWebMessageReceived, w.browser.webview.VTBL.RemoveWebMessageReceived, unsafe.Pointer(messageReceived))
+
+	if w.config.WindowConfig.DisableZoom {
+		w.disableZoom()
+	}
+
+	moveFocusRequested := &moveFocusRequestedHandler{webview: w}
+	moveFocusRequested.VTBL = moveFocusRequestedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.controller), w.browser.controller.VTBL.AddMoveFocusRequested, w.browser.controller.VTBL.RemoveMoveFocusRequested, unsafe.Pointer(moveFocusRequested))
+
+	fullscreenChanged := &fullscreenChangedHandler{webview: w}
+	fullscreenChanged.VTBL = fullscreenChangedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.webview), w.browser.webview.VTBL.AddContainsFullScreenElementChanged, w.browser.webview.VTBL.RemoveContainsFullScreenElementChanged, unsafe.Pointer(fullscreenChanged))
+
+	acceleratorKeyPressed := &acceleratorKeyPressedHandler{webview: w}
+	acceleratorKeyPressed.VTBL = acceleratorKeyPressedHandlerVTBL
+	w.addEvent(unsafe.Pointer(w.browser.controller), w.browser.controller.VTBL.AddAcceleratorKeyPressed, w.browser.controller.VTBL.RemoveAcceleratorKeyPressed, unsafe.Pointer(acceleratorKeyPressed))
+
+	if w.config.WindowConfig.DisableAccelerators == AcceleratorsAll {
+		w.disableAccelerators()
+	}
+
+	w.addContextMenuRequested()
+	w.addScript(contextMenuScript)
+	w.addScript(notificationScript(w.notifications))
+	if w.config.WindowConfig.AllowClipboardWrite {
+		w.addScript(clipboardScript)
+	}
+	w.applyContextMenu()
+	w.applyStyle()
+}
+
+// addScript adds the JavaScript code, which runs on every page before any script of the page. It must be called from
+// the UI thread.
+func (w *webview) addScript(script string) {
+	h := &scriptHandler{}
+	h.VTBL = scriptHandlerVTBL
+	pending.Store(h, true)
+
+	res, _, _ := syscall.Syscall(w.browser.webview.VTBL.AddScriptToExecuteOnDocumentCreated, 3, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(script))), uintptr(unsafe.Pointer(h)))
+	if res != 0 {
+		pending.Delete(h)
+	}
+}
+
+// eval runs the JavaScript code on the current page. It must be called from the UI thread.
+func (w *webview) eval(script string) {
+	if w.browser.webview == nil {
 		return
 	}
 
-	w.addEnv(` --proxy-server="%s"`, proxy.String())
+	h := &evalHandler{}
+	h.VTBL = evalHandlerVTBL
+	pending.Store(h, true)
+
+	res, _, _ := syscall.Syscall(w.browser.webview.VTBL.ExecuteScript, 3, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(script))), uintptr(unsafe.Pointer(h)))
+	if res != 0 {
+		pending.Delete(h)
+	}
 }
 
-func (w *webview) setCerts(certs []x509.Certificate) {
-	if certs == nil || len(certs) == 0 {
+// message handles the messages sent by the scripts of gowebview, other messages are ignored. The source is the URL
+// of the page which sent the message. It must be called from the UI thread.
+func (w *webview) message(source, msg string) {
+	if strings.HasPrefix(msg, messageNotification) {
+		w.webNotification(msg[len(messageNotification):])
 		return
 	}
 
-	var jcerts string
-	h := sha256.New()
-	for _, c := range certs {
-		h.Write(c.RawSubjectPublicKeyInfo)
-		jcerts += base64.StdEncoding.EncodeToString(h.Sum(nil)) + ","
-		h.Reset()
+	if strings.HasPrefix(msg, messageNotificationClose) {
+		w.closeWebNotification(msg[len(messageNotificationClose):])
+		return
 	}
 
-	w.addEnv(` --ignore-certificate-errors-spki-list="%s"`, jcerts)
+	if strings.HasPrefix(msg, messageClipboard) {
+		if w.config.WindowConfig.AllowClipboardWrite && clipboardOrigin(source, w.config) && w.userGesture() {
+			w.Clipboard().WriteText(msg[len(messageClipboard):])
+		}
+		return
+	}
+
+	switch msg {
+	case messageDrag:
+		w.drag()
+	case messageMaximize:
+		w.toggleMaximize()
+	case messageContextMenu:
+		if w.nativeContextMenu() {
+			return
+		}
+
+		// The menu runs its own message loop, which must not run inside the handler of the browser.
+		w.thread.dispatch(w.showContextMenu)
+	}
 }
 
-func (w *webview) addEnv(argument, value string) {
-	os.Setenv("WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS", os.Getenv("WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS")+" "+fmt.Sprintf(argument, value))
+// disableZoom prevents the user from changing the zoom. The pinch zoom is only disabled on newer runtimes. It must be
+// called from the UI thread.
+func (w *webview) disableZoom() {
+	var settings *wincom.ICoreWebView2Settings
+	if res, _, _ := syscall.Syscall(w.browser.webview.VTBL.GetSettings, 2, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(&settings)), 0); res != 0 || settings == nil {
+		return
+	}
+	defer syscall.Syscall(settings.VTBL.Release, 1, uintptr(unsafe.Pointer(settings)), 0, 0)
+
+	syscall.Syscall(settings.VTBL.PutIsZoomControlEnabled, 2, uintptr(unsafe.Pointer(settings)), 0, 0)
+
+	var settings5 *wincom.ICoreWebView2Settings
+	res, _, _ := syscall.Syscall(settings.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(settings)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2Settings5)), uintptr(unsafe.Pointer(&settings5)))
+	if res == 0 && settings5 != nil {
+		syscall.Syscall(settings5.VTBL.PutIsPinchZoomEnabled, 2, uintptr(unsafe.Pointer(settings5)), 0, 0)
+		syscall.Syscall(settings5.VTBL.Release, 1, uintptr(unsafe.Pointer(settings5)), 0, 0)
+	}
 }
 
-func (w *webview) create() error {
-	cerr := make(chan error, 1<<2)
+// addEvent adds the event handler, using the add and remove functions of the VTBL of the target, such as
+// ICoreWebView2 or ICoreWebView2Controller. The handler is removed when the browser is destroyed.
+func (w *webview) addEvent(target unsafe.Pointer, add, remove uintptr, h unsafe.Pointer) {
+	var token wincom.EventRegistrationToken
 
-	go func() {
-		runtime.LockOSThread()
-		w32.CoInitializeEx(w32.COINIT_APARTMENTTHREADED)
+	handlers.Store(h, true)
+	syscall.Syscall(add, 3, uintptr(target), uintptr(h), uintptr(unsafe.Pointer(&token)))
 
-		go func() {
-			<-w.done
-			cerr <- nil
-		}()
+	w.browser.events = append(w.browser.events, func() {
+		syscall.Syscall(remove, 2, uintptr(target), uintptr(token), 0)
+		handlers.Delete(h)
+	})
+}
 
-		if err := w.createWindow(); err != nil {
-			cerr <- err
-			return
+func (w *webview) OnCloseRequested(f func() bool) {
+	w.thread.dispatch(func() {
+		w.onClose.Sync = append(w.onClose.Sync, f)
+	})
+}
+
+func (w *webview) OnCloseRequestedAsync(f func(reply func(allow bool))) {
+	w.thread.dispatch(func() {
+		w.onClose.Async = append(w.onClose.Async, f)
+	})
+}
+
+// requestClose closes the window if all functions of OnCloseRequested allow it. It must be called from the UI thread.
+func (w *webview) requestClose() {
+	w.onClose.Request(func(f func()) { w.thread.dispatch(f) }, func() {
+		w.close(ErrWindowClosed)
+	})
+}
+
+func (w *webview) OnProcessFailed(f func(failure ProcessFailure)) {
+	w.thread.dispatch(func() {
+		w.onProcessFailed = append(w.onProcessFailed, f)
+	})
+}
+
+// processFailed notifies the failure and tries to recover, if the Config.AutoRecover is defined. It must be called
+// from the UI thread.
+func (w *webview) processFailed(failure ProcessFailure) {
+	if failure.Kind != ProcessFailureBrowserExited {
+		if source := w.source(); source != "" {
+			w.url = source
 		}
+	}
 
-		watchlist.Store(w.view.window, w)
-		defer watchlist.Delete(w.view.window)
+	var delay time.Duration
+	if w.backoff != nil {
+		delay, failure.Recovering = w.backoff.Failed(time.Now())
+	}
 
-		res, _, err := w.dll.Call(0, uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(w.config.WindowConfig.Path))), 0, w.environmentCompletedHandler())
-		if res != 0 {
-			cerr <- err
-			return
+	for _, f := range w.onProcessFailed {
+		f(failure)
+	}
+
+	if !failure.Recovering {
+		// The render process can be restarted with a reload, but without the browser process the WebView is useless.
+		if failure.Kind == ProcessFailureBrowserExited || w.backoff != nil {
+			w.close(ErrBrowserProcessFailed)
 		}
+		return
+	}
 
-		if err := w.loop(); err != nil {
+	time.AfterFunc(delay, func() {
+		w.thread.dispatch(func() {
+			w.recover(failure.Kind)
+		})
+	})
+}
+
+// recover reloads the last URL, the browser is recreated if the browser process exited. It must be called from the
+// UI thread.
+func (w *webview) recover(kind ProcessFailureKind) {
+	if w.view.window == 0 {
+		return
+	}
+
+	if kind != ProcessFailureBrowserExited && w.browser.webview != nil {
+		w.navigate(w.url)
+		return
+	}
+
+	environment := w.browser.environment
+	w.destroyBrowser()
+	w.thread.resetEnvironment(environment)
+
+	w.createBrowser(func(err error) {
+		if err != nil {
+			w.processFailed(ProcessFailure{Kind: ProcessFailureBrowserExited, ExitCode: -1})
 			return
 		}
-	}()
 
-	return <-cerr
+		w.updateSize(true)
+		w.navigate(w.url)
+	})
 }
 
 func (w *webview) Run() {
-	<-w.done
+	w.RunContext(context.Background())
 }
 
-func (w *webview) loop() (err error) {
-	msg := new(w32.MSG)
-loop:
-	for {
-		select {
-		case f := <-w.queue:
-			f()
-		default:
-			m := w32.GetMessage(msg, 0, 0, 0)
-			switch m {
-			case -1:
-				return errors.New("GetMessage fails")
-			case 0:
-				break loop
-			}
+func (w *webview) RunContext(ctx context.Context) error {
+	select {
+	case <-w.done:
+		return w.reason
+	case <-ctx.Done():
+		w.Destroy()
+		return ctx.Err()
+	}
+}
 
-			w32.TranslateMessage(msg)
-			w32.DispatchMessage(msg)
-		}
+func (w *webview) Terminate() {
+	w.thread.dispatch(func() {
+		w.close(ErrTerminated)
+	})
+}
+
+func (w *webview) Destroy() {
+	w.thread.queue.DispatchSync(context.Background(), func() error {
+		w.close(ErrTerminated)
+		return nil
+	})
+
+	// The thread is stopped when the window is closed, the profile is released by the thread.
+	if w.standalone {
+		w.thread.wait()
 	}
+}
 
-	return nil
+// close destroys the window and stops the Run with the given reason, it must be called from the UI thread. It does
+// nothing if the window is already closed.
+func (w *webview) close(reason error) {
+	if w.view.window == 0 {
+		return
+	}
+
+	// The failure to save the state doesn't keep the window open, the RunContext reports it with the reason.
+	if err := w.saveState(); err != nil {
+		reason = &kindError{kind: reason, err: err}
+	}
+	w.destroy()
+	w.finish(reason)
+
+	if w.closed != nil {
+		w.closed()
+	}
 }
 
-func (w *webview) Terminate() {
-	w.queue <- func() {
-		w32.PostQuitMessage(0)
-		w32.DestroyWindow(w.view.window)
-		w.done <- true
+// saveState saves the WindowState into the StateStore, it must be called from the UI thread before the window is
+// destroyed.
+func (w *webview) saveState() error {
+	store := w.config.WindowConfig.StateStore
+	if store == nil || w.view.parent != 0 {
+		return nil
+	}
+
+	placement := w32.WINDOWPLACEMENT{Length: uint32(unsafe.Sizeof(w32.WINDOWPLACEMENT{}))}
+	if !w32.GetWindowPlacement(w.view.window, &placement) {
+		return errors.New("GetWindowPlacement fails")
 	}
+
+	// The fullscreen isn't saved, the placement before the fullscreen is used instead.
+	if w.view.fullscreen {
+		placement = w.view.placement
+	}
+
+	// The normal position uses the workspace coordinates, which differs from the screen coordinates when the
+	// taskbar is at the top or left. So, it's only used when the window is maximized, minimized or fullscreen.
+	state := &WindowState{Bounds: rectFrom(placement.RcNormalPosition), Visibility: VisibilityDefault}
+	if placement.ShowCmd == w32.SW_SHOWNORMAL && !w.view.fullscreen {
+		state.Bounds = rectFrom(*w32.GetWindowRect(w.view.window))
+	}
+
+	// WPF_RESTORETOMAXIMIZED (0x02) is set when the window is minimized after being maximized.
+	if placement.ShowCmd == w32.SW_SHOWMAXIMIZED || (placement.ShowCmd == w32.SW_SHOWMINIMIZED && placement.Flags&0x02 != 0) {
+		state.Visibility = VisibilityMaximized
+	}
+
+	return store.Save(state)
 }
 
-func (w *webview) Destroy() {
-	w.Terminate()
+func (w *webview) finish(reason error) {
+	w.reason = reason
+	close(w.done)
+}
+
+// destroy closes the browser and the native window, it must be called from the UI thread.
+func (w *webview) destroy() {
+	if w.view.window == 0 {
+		return
+	}
+
+	w.destroyBrowser()
+	w.unhook()
+
+	watchlist.Delete(w.view.window)
+	delete(w.thread.views, w)
+	w32.DestroyWindow(w.view.window)
+	w.view.window = 0
+
+	w.destroyIcons()
+	if w.view.badge != 0 {
+		w32.DestroyIcon(w.view.badge)
+		w.view.badge = 0
+	}
+
+	w.release()
+}
+
+// destroyBrowser closes the browser, but keeps the native window. It must be called from the UI thread.
+func (w *webview) destroyBrowser() {
+	if w.browser.controller == nil {
+		return
+	}
+
+	for _, remove := range w.browser.events {
+		remove()
+	}
+
+	syscall.Syscall(w.browser.controller.VTBL.Close, 1, uintptr(unsafe.Pointer(w.browser.controller)), 0, 0)
+	syscall.Syscall(w.browser.webview.VTBL.Release, 1, uintptr(unsafe.Pointer(w.browser.webview)), 0, 0)
+	syscall.Syscall(w.browser.controller.VTBL.Release, 1, uintptr(unsafe.Pointer(w.browser.controller)), 0, 0)
+	w.browser = browser{}
+}
+
+func (w *webview) Dispatch(f func()) {
+	w.thread.dispatch(f)
+}
+
+func (w *webview) DispatchSync(ctx context.Context, f func() error) error {
+	return w.thread.queue.DispatchSync(ctx, f)
 }
 
 func (w *webview) Window() uintptr {
@@ -197,9 +594,9 @@ func (w *webview) Window() uintptr {
 }
 
 func (w *webview) SetTitle(title string) {
-	w.queue <- func() {
+	w.thread.dispatch(func() {
 		w32.SetWindowText(w.view.window, title)
-	}
+	})
 }
 
 func (w *webview) SetSize(point *Point, hint Hint) {
@@ -211,9 +608,9 @@ func (w *webview) SetSize(point *Point, hint Hint) {
 
 	switch hint {
 	case HintNone:
-		w.queue <- func() {
-			w32.SetWindowPos(w.view.window, w32.HWND_TOP, 0, 0, int(point.X), int(point.Y), w32.SWP_NOMOVE)
-		}
+		w.thread.dispatch(func() {
+			w32.SetWindowPos(w.view.window, w32.HWND_TOP, 0, 0, w.physical(point.X), w.physical(point.Y), w32.SWP_NOMOVE)
+		})
 	case HintFixed:
 		w.view.min = *point
 		w.view.max = *point
@@ -235,7 +632,7 @@ func (w *webview) updateSize(now bool) {
 		return
 	}
 
-	w.queue <- f
+	w.thread.dispatch(f)
 }
 
 func (w *webview) SetURL(url string) {
@@ -243,23 +640,724 @@ func (w *webview) SetURL(url string) {
 		url = w.config.URL
 	}
 
-	w.queue <- func() {
-		syscall.Syscall(w.browser.webview.VTBL.Navigate, 2, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(url))), 0)
+	w.thread.dispatch(func() {
+		w.url = url
+		w.navigate(url)
+	})
+}
+
+// navigate opens the URL, it must be called from the UI thread.
+func (w *webview) navigate(url string) {
+	if w.browser.webview == nil || url == "" {
+		return
 	}
+
+	syscall.Syscall(w.browser.webview.VTBL.Navigate, 2, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(url))), 0)
 }
 
 func (w *webview) SetVisibility(v Visibility) {
+	w.thread.dispatch(func() {
+		w.setVisibility(v)
+	})
+}
+
+// setVisibility must be called from the UI thread.
+func (w *webview) setVisibility(v Visibility) {
+	// The child window can't be maximized or fullscreen, it's only displayed or hidden.
+	if w.view.parent != 0 {
+		if v == VisibilityMinimized || v == VisibilityHidden {
+			w32.ShowWindow(w.view.window, w32.SW_HIDE)
+		} else {
+			w32.ShowWindow(w.view.window, w32.SW_SHOW)
+		}
+		return
+	}
+
+	if v == VisibilityFullscreen {
+		w.setFullscreen(true)
+		w32.ShowWindow(w.view.window, w32.SW_SHOW)
+		w32.SetForegroundWindow(w.view.window)
+		return
+	}
+
+	w.setFullscreen(false)
+
 	switch v {
 	case VisibilityMaximized:
 		w32.ShowWindow(w.view.window, w32.SW_MAXIMIZE)
 	case VisibilityMinimized:
 		w32.ShowWindow(w.view.window, w32.SW_MINIMIZE)
+	case VisibilityHidden:
+		w32.ShowWindow(w.view.window, w32.SW_HIDE)
 	default:
 		w32.ShowWindow(w.view.window, w32.SW_SHOWDEFAULT)
 		w32.SetForegroundWindow(w.view.window)
 	}
 }
 
+func (w *webview) SetFullscreen(fullscreen bool) {
+	w.thread.dispatch(func() {
+		w.setFullscreen(fullscreen)
+	})
+}
+
+func (w *webview) OnFullscreenChanged(f func(fullscreen bool)) {
+	w.thread.dispatch(func() {
+		w.onFullscreenChanged = append(w.onFullscreenChanged, f)
+	})
+}
+
+// setFullscreen removes the borders and covers the monitor of the window, or restores the previous style and
+// placement. It must be called from the UI thread.
+func (w *webview) setFullscreen(fullscreen bool) {
+	w.view.byElement = false
+	if w.view.window == 0 || w.view.parent != 0 || w.view.fullscreen == fullscreen {
+		return
+	}
+
+	if fullscreen {
+		placement := w32.WINDOWPLACEMENT{Length: uint32(unsafe.Sizeof(w32.WINDOWPLACEMENT{}))}
+		if !w32.GetWindowPlacement(w.view.window, &placement) {
+			return
+		}
+
+		info := w32.MONITORINFO{CbSize: uint32(unsafe.Sizeof(w32.MONITORINFO{}))}
+		if !w32.GetMonitorInfo(w32.MonitorFromWindow(w.view.window, w32.MONITOR_DEFAULTTONEAREST), &info) {
+			return
+		}
+
+		w.view.placement = placement
+		w.view.style = w32.GetWindowLong(w.view.window, w32.GWL_STYLE)
+		w32.SetWindowLong(w.view.window, w32.GWL_STYLE, w.view.style&^(w32.WS_CAPTION|w32.WS_THICKFRAME))
+
+		r := info.RcMonitor
+		w32.SetWindowPos(w.view.window, 0, int(r.Left), int(r.Top), int(r.Right-r.Left), int(r.Bottom-r.Top), w32.SWP_NOZORDER|w32.SWP_NOOWNERZORDER|w32.SWP_FRAMECHANGED)
+	} else {
+		w32.SetWindowLong(w.view.window, w32.GWL_STYLE, w.view.style)
+		w32.SetWindowPlacement(w.view.window, &w.view.placement)
+		w32.SetWindowPos(w.view.window, 0, 0, 0, 0, 0, w32.SWP_NOMOVE|w32.SWP_NOSIZE|w32.SWP_NOZORDER|w32.SWP_NOOWNERZORDER|w32.SWP_FRAMECHANGED)
+	}
+
+	w.view.fullscreen = fullscreen
+	w.updateSize(true)
+
+	for _, f := range w.onFullscreenChanged {
+		f(fullscreen)
+	}
+}
+
+// elementFullscreen follows the fullscreen of the page, such as the fullscreen button of a video. It only leaves the
+// fullscreen if it was entered by the page. It must be called from the UI thread.
+func (w *webview) elementFullscreen(fullscreen bool) {
+	if fullscreen == w.view.fullscreen {
+		return
+	}
+
+	if fullscreen {
+		w.setFullscreen(true)
+		w.view.byElement = w.view.fullscreen
+		return
+	}
+
+	if w.view.byElement {
+		w.setFullscreen(false)
+	}
+}
+
+func (w *webview) Bounds() (bounds Rect) {
+	w.thread.queue.DispatchSync(context.Background(), func() error {
+		if w.view.window == 0 {
+			return nil
+		}
+
+		bounds = rectFrom(*w32.GetWindowRect(w.view.window))
+		if w.view.parent != 0 {
+			x, y, _ := w32.ScreenToClient(w.view.parent, int(bounds.X), int(bounds.Y))
+			bounds.X, bounds.Y = int64(x), int64(y)
+		}
+		return nil
+	})
+
+	return bounds
+}
+
+func (w *webview) SetBounds(bounds Rect) {
+	w.thread.dispatch(func() {
+		w32.SetWindowPos(w.view.window, 0, int(bounds.X), int(bounds.Y), int(bounds.Width), int(bounds.Height), w32.SWP_NOZORDER|w32.SWP_NOACTIVATE)
+	})
+}
+
+func (w *webview) Scale() (scale float64) {
+	w.thread.queue.DispatchSync(context.Background(), func() error {
+		scale = w.view.scale
+		return nil
+	})
+
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
+
+func (w *webview) Zoom() (factor float64) {
+	w.thread.queue.DispatchSync(context.Background(), func() error {
+		factor = w.zoom()
+		return nil
+	})
+
+	return factor
+}
+
+// zoom returns the current zoom factor, or 1 if the browser isn't available. It must be called from the UI thread.
+func (w *webview) zoom() float64 {
+	if w.browser.controller == nil {
+		return 1
+	}
+
+	var factor float64
+	if res, _, _ := syscall.Syscall(w.browser.controller.VTBL.GetZoomFactor, 2, uintptr(unsafe.Pointer(w.browser.controller)), uintptr(unsafe.Pointer(&factor)), 0); res != 0 || factor == 0 {
+		return 1
+	}
+	return factor
+}
+
+func (w *webview) OnZoomChanged(f func(factor float64)) {
+	w.thread.dispatch(func() {
+		w.onZoomChanged = append(w.onZoomChanged, f)
+	})
+}
+
+func (w *webview) SetZoom(factor float64) {
+	w.thread.dispatch(func() {
+		w.setZoom(factor)
+	})
+}
+
+// setZoom changes the zoom factor, it must be called from the UI thread.
+func (w *webview) setZoom(factor float64) {
+	if w.browser.controller == nil {
+		return
+	}
+
+	syscall.Syscall(w.browser.controller.VTBL.PutZoomFactor, 2, uintptr(unsafe.Pointer(w.browser.controller)), uintptr(math.Float64bits(factor)), 0)
+}
+
+// physical converts the logical units into pixels, using the scale of the current monitor. It must be called from
+// the UI thread.
+func (w *webview) physical(v int64) int {
+	if w.view.scale == 0 {
+		return int(v)
+	}
+	return int(math.Round(float64(v) * w.view.scale))
+}
+
+func (w *webview) Center() {
+	w.thread.dispatch(func() {
+		if w.view.window == 0 {
+			return
+		}
+
+		var area Rect
+		if w.view.parent != 0 {
+			// The child window is centered on the client area of the parent.
+			r := w32.GetClientRect(w.view.parent)
+			if r == nil {
+				return
+			}
+			area = rectFrom(*r)
+		} else {
+			screen, ok := screenOf(w32.MonitorFromWindow(w.view.window, w32.MONITOR_DEFAULTTONEAREST))
+			if !ok {
+				return
+			}
+			area = screen.WorkArea
+		}
+
+		b := rectFrom(*w32.GetWindowRect(w.view.window))
+		x := area.X + (area.Width-b.Width)/2
+		y := area.Y + (area.Height-b.Height)/2
+
+		w32.SetWindowPos(w.view.window, 0, int(x), int(y), 0, 0, w32.SWP_NOSIZE|w32.SWP_NOZORDER|w32.SWP_NOACTIVATE)
+	})
+}
+
+func (w *webview) Screens() (list []Screen) {
+	w.thread.queue.DispatchSync(context.Background(), func() error {
+		list = screens()
+		return nil
+	})
+
+	return list
+}
+
+func (w *webview) ClearBrowsingData(ctx context.Context, kinds DataKinds, since time.Time) error {
+	err := w.clearProfile(ctx, kinds, since)
+	if err == errProfileUnsupported {
+		err = w.clearOrigin(ctx, kinds, since)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The back/forward list belongs to the page, not to the profile.
+	if kinds&DataHistory != 0 {
+		return w.callDevTools(ctx, "Page.resetNavigationHistory", nil)
+	}
+	return nil
+}
+
+// errProfileUnsupported is returned by clearProfile when the runtime doesn't have ICoreWebView2Profile2.
+var errProfileUnsupported = errors.New("ICoreWebView2Profile2 not supported")
+
+// browsingDataKinds are the COREWEBVIEW2_BROWSING_DATA_KINDS of each DataKinds.
+var browsingDataKinds = map[DataKinds]uint32{
+	DataCookies:        wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_COOKIES,
+	DataCache:          wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_DISK_CACHE,
+	DataLocalStorage:   wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_LOCAL_STORAGE,
+	DataIndexedDB:      wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_INDEXED_DB | wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_WEB_SQL,
+	DataServiceWorkers: wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_SERVICE_WORKERS | wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_CACHE_STORAGE,
+	DataHistory:        wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_BROWSING_HISTORY | wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_DOWNLOAD_HISTORY,
+}
+
+// clearProfile removes the data of all origins, using the ICoreWebView2Profile2. The DataAll also removes the
+// autofill, saved passwords and site settings.
+func (w *webview) clearProfile(ctx context.Context, kinds DataKinds, since time.Time) error {
+	var dataKinds uint32
+	for k, v := range browsingDataKinds {
+		if kinds&k != 0 {
+			dataKinds |= v
+		}
+	}
+	if kinds&DataAll == DataAll {
+		dataKinds = wincom.COREWEBVIEW2_BROWSING_DATA_KINDS_ALL_PROFILE
+	}
+
+	h := &clearDataHandler{result: make(chan error, 1)}
+	h.VTBL = clearDataHandlerVTBL
+
+	ok := w.thread.dispatch(func() {
+		profile, err := w.profile()
+		if err != nil {
+			h.result <- err
+			return
+		}
+		defer syscall.Syscall(profile.VTBL.Release, 1, uintptr(unsafe.Pointer(profile)), 0, 0)
+
+		pending.Store(h, true)
+
+		var res uintptr
+		if since.IsZero() {
+			res, _, _ = syscall.Syscall(profile.VTBL.ClearBrowsingData, 3, uintptr(unsafe.Pointer(profile)), uintptr(dataKinds), uintptr(unsafe.Pointer(h)))
+		} else {
+			// The times are seconds since the UNIX epoch, the double arguments are also copied to the XMM registers
+			// by the syscall.
+			start := math.Float64bits(float64(since.UnixNano()) / 1e9)
+			end := math.Float64bits(float64(time.Now().UnixNano()) / 1e9)
+			res, _, _ = syscall.Syscall6(profile.VTBL.ClearBrowsingDataInTimeRange, 5, uintptr(unsafe.Pointer(profile)), uintptr(dataKinds), uintptr(start), uintptr(end), uintptr(unsafe.Pointer(h)), 0)
+		}
+		if res != 0 {
+			pending.Delete(h)
+			h.result <- hresult("ClearBrowsingData", res)
+		}
+	})
+	if !ok {
+		return ErrClosed
+	}
+
+	// The handler is never called if the thread ends first.
+	select {
+	case err := <-h.result:
+		return err
+	case <-w.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// profile returns the ICoreWebView2Profile2, which must be released. It must be called from the UI thread.
+func (w *webview) profile() (*wincom.ICoreWebView2Profile2, error) {
+	if w.browser.webview == nil {
+		return nil, ErrClosed
+	}
+
+	var webview13 *wincom.ICoreWebView2_13
+	res, _, _ := syscall.Syscall(w.browser.webview.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2_13)), uintptr(unsafe.Pointer(&webview13)))
+	if res != 0 || webview13 == nil {
+		return nil, errProfileUnsupported
+	}
+	defer syscall.Syscall(webview13.VTBL.Release, 1, uintptr(unsafe.Pointer(webview13)), 0, 0)
+
+	var profile *wincom.ICoreWebView2Profile2
+	res, _, _ = syscall.Syscall(webview13.VTBL.GetProfile, 2, uintptr(unsafe.Pointer(webview13)), uintptr(unsafe.Pointer(&profile)), 0)
+	if res != 0 || profile == nil {
+		return nil, errProfileUnsupported
+	}
+	defer syscall.Syscall(profile.VTBL.Release, 1, uintptr(unsafe.Pointer(profile)), 0, 0)
+
+	var profile2 *wincom.ICoreWebView2Profile2
+	res, _, _ = syscall.Syscall(profile.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(profile)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2Profile2)), uintptr(unsafe.Pointer(&profile2)))
+	if res != 0 || profile2 == nil {
+		return nil, errProfileUnsupported
+	}
+	return profile2, nil
+}
+
+// clearOrigin is used on older runtimes, which don't have the ICoreWebView2Profile2. The cookies and the cache are
+// removed from the whole profile, but the DevTools Protocol can only clear the storage of one origin. It clears the
+// storage of the current page, and returns ErrFeatureNotSupported since the storage of other origins remains.
+func (w *webview) clearOrigin(ctx context.Context, kinds DataKinds, since time.Time) error {
+	if !since.IsZero() {
+		return ErrFeatureNotSupported
+	}
+
+	if kinds&DataCookies != 0 {
+		if err := w.callDevTools(ctx, "Network.clearBrowserCookies", nil); err != nil {
+			return err
+		}
+	}
+
+	if kinds&DataCache != 0 {
+		if err := w.callDevTools(ctx, "Network.clearBrowserCache", nil); err != nil {
+			return err
+		}
+	}
+
+	var storages []string
+	if kinds&DataLocalStorage != 0 {
+		storages = append(storages, "local_storage")
+	}
+	if kinds&DataIndexedDB != 0 {
+		storages = append(storages, "indexeddb", "websql")
+	}
+	if kinds&DataServiceWorkers != 0 {
+		storages = append(storages, "service_workers", "cache_storage")
+	}
+	if len(storages) == 0 {
+		return nil
+	}
+
+	origin, err := w.origin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if origin != "" {
+		err := w.callDevTools(ctx, "Storage.clearDataForOrigin", map[string]string{
+			"origin":       origin,
+			"storageTypes": strings.Join(storages, ","),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return &kindError{kind: ErrFeatureNotSupported, err: errors.New("the storage of other origins needs a newer WebView2 runtime, use Profile.Remove")}
+}
+
+// origin returns the origin (scheme://host:port) of the current page, or empty if the page doesn't have one, such as
+// "about:blank" or "data:" pages.
+func (w *webview) origin(ctx context.Context) (string, error) {
+	source := make(chan string, 1)
+	ok := w.thread.dispatch(func() {
+		source <- w.source()
+	})
+	if !ok {
+		return "", ErrClosed
+	}
+
+	select {
+	case <-w.done:
+		return "", ErrClosed
+	case s := <-source:
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return "", nil
+		}
+		return u.Scheme + "://" + u.Host, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// source returns the URL of the current page, it must be called from the UI thread.
+func (w *webview) source() string {
+	if w.browser.webview == nil {
+		return ""
+	}
+
+	var s *uint16
+	syscall.Syscall(w.browser.webview.VTBL.GetSource, 2, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(&s)), 0)
+	if s == nil {
+		return ""
+	}
+	defer windows.CoTaskMemFree(unsafe.Pointer(s))
+
+	return windows.UTF16PtrToString(s)
+}
+
+// callDevTools calls the given method of the DevTools Protocol (https://chromedevtools.github.io/devtools-protocol/)
+// and waits until it completes.
+func (w *webview) callDevTools(ctx context.Context, method string, params interface{}) error {
+	p := []byte("{}")
+	if params != nil {
+		var err error
+		if p, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+
+	h := &devToolsHandler{result: make(chan error, 1)}
+	h.VTBL = devToolsHandlerVTBL
+
+	ok := w.thread.dispatch(func() {
+		if w.browser.webview == nil {
+			h.result <- ErrClosed
+			return
+		}
+
+		pending.Store(h, true)
+
+		res, _, _ := syscall.Syscall6(w.browser.webview.VTBL.CallDevToolsProtocolMethod, 4,
+			uintptr(unsafe.Pointer(w.browser.webview)),
+			uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(method))),
+			uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(string(p)))),
+			uintptr(unsafe.Pointer(h)),
+			0, 0,
+		)
+		if res != 0 {
+			pending.Delete(h)
+			h.result <- hresult("CallDevToolsProtocolMethod", res)
+		}
+	})
+	if !ok {
+		return ErrClosed
+	}
+
+	select {
+	case err := <-h.result:
+		return err
+	case <-w.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// devToolsHandler implements ICoreWebView2CallDevToolsProtocolMethodCompletedHandler. All handlers share the same
+// VTBL, since the callbacks created by windows.NewCallback are never released.
+type devToolsHandler struct {
+	wincom.ICoreWebView2CallDevToolsProtocolMethodCompletedHandler
+	result chan error
+}
+
+var devToolsHandlerVTBL = &wincom.ICoreWebView2CallDevToolsProtocolMethodCompletedHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *devToolsHandler, errorCode uintptr, _ *uint16) uintptr {
+		pending.Delete(h)
+
+		if errorCode != 0 {
+			h.result <- hresult("CallDevToolsProtocolMethod", errorCode)
+			return 0
+		}
+
+		h.result <- nil
+		return 0
+	}),
+}
+
+// clearDataHandler implements ICoreWebView2ClearBrowsingDataCompletedHandler.
+type clearDataHandler struct {
+	wincom.ICoreWebView2ClearBrowsingDataCompletedHandler
+	result chan error
+}
+
+var clearDataHandlerVTBL = &wincom.ICoreWebView2ClearBrowsingDataCompletedHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *clearDataHandler, errorCode uintptr) uintptr {
+		pending.Delete(h)
+
+		if errorCode != 0 {
+			h.result <- hresult("ClearBrowsingData", errorCode)
+			return 0
+		}
+
+		h.result <- nil
+		return 0
+	}),
+}
+
+// processFailedHandler implements ICoreWebView2ProcessFailedEventHandler.
+type processFailedHandler struct {
+	wincom.ICoreWebView2ProcessFailedEventHandler
+	webview *webview
+}
+
+var processFailedHandlerVTBL = &wincom.ICoreWebView2ProcessFailedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *processFailedHandler, _ *wincom.ICoreWebView2, args *wincom.ICoreWebView2ProcessFailedEventArgs) uintptr {
+		var kind uint32
+		syscall.Syscall(args.VTBL.GetProcessFailedKind, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&kind)), 0)
+
+		failure := ProcessFailure{ExitCode: -1}
+		switch kind {
+		case wincom.COREWEBVIEW2_PROCESS_FAILED_KIND_BROWSER_PROCESS_EXITED:
+			failure.Kind = ProcessFailureBrowserExited
+		case wincom.COREWEBVIEW2_PROCESS_FAILED_KIND_RENDER_PROCESS_UNRESPONSIVE:
+			failure.Kind = ProcessFailureRenderUnresponsive
+		default:
+			failure.Kind = ProcessFailureRenderExited
+		}
+
+		// The exit code is only available on newer runtimes.
+		var args2 *wincom.ICoreWebView2ProcessFailedEventArgs2
+		res, _, _ := syscall.Syscall(args.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2ProcessFailedEventArgs2)), uintptr(unsafe.Pointer(&args2)))
+		if res == 0 && args2 != nil {
+			var code int32
+			if res, _, _ := syscall.Syscall(args2.VTBL.GetExitCode, 2, uintptr(unsafe.Pointer(args2)), uintptr(unsafe.Pointer(&code)), 0); res == 0 && failure.Kind != ProcessFailureRenderUnresponsive {
+				failure.ExitCode = int(code)
+			}
+			syscall.Syscall(args2.VTBL.Release, 1, uintptr(unsafe.Pointer(args2)), 0, 0)
+		}
+
+		h.webview.processFailed(failure)
+		return 0
+	}),
+}
+
+// closeRequestedHandler implements ICoreWebView2WindowCloseRequestedEventHandler.
+type closeRequestedHandler struct {
+	wincom.ICoreWebView2WindowCloseRequestedEventHandler
+	webview *webview
+}
+
+var closeRequestedHandlerVTBL = &wincom.ICoreWebView2WindowCloseRequestedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *closeRequestedHandler, _ *wincom.ICoreWebView2, _ uintptr) uintptr {
+		h.webview.requestClose()
+		return 0
+	}),
+}
+
+// zoomChangedHandler implements ICoreWebView2ZoomFactorChangedEventHandler.
+type zoomChangedHandler struct {
+	wincom.ICoreWebView2ZoomFactorChangedEventHandler
+	webview *webview
+}
+
+var zoomChangedHandlerVTBL = &wincom.ICoreWebView2ZoomFactorChangedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *zoomChangedHandler, _ *wincom.ICoreWebView2Controller, _ uintptr) uintptr {
+		factor := h.webview.zoom()
+		for _, f := range h.webview.onZoomChanged {
+			f(factor)
+		}
+		return 0
+	}),
+}
+
+// fullscreenChangedHandler implements ICoreWebView2ContainsFullScreenElementChangedEventHandler.
+type fullscreenChangedHandler struct {
+	wincom.ICoreWebView2ContainsFullScreenElementChangedEventHandler
+	webview *webview
+}
+
+var fullscreenChangedHandlerVTBL = &wincom.ICoreWebView2ContainsFullScreenElementChangedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *fullscreenChangedHandler, sender *wincom.ICoreWebView2, _ uintptr) uintptr {
+		var contains int32
+		syscall.Syscall(sender.VTBL.GetContainsFullScreenElement, 2, uintptr(unsafe.Pointer(sender)), uintptr(unsafe.Pointer(&contains)), 0)
+
+		h.webview.elementFullscreen(contains != 0)
+		return 0
+	}),
+}
+
+// moveFocusRequestedHandler implements ICoreWebView2MoveFocusRequestedEventHandler.
+type moveFocusRequestedHandler struct {
+	wincom.ICoreWebView2MoveFocusRequestedEventHandler
+	webview *webview
+}
+
+var moveFocusRequestedHandlerVTBL = &wincom.ICoreWebView2MoveFocusRequestedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *moveFocusRequestedHandler, _ *wincom.ICoreWebView2Controller, args *wincom.ICoreWebView2MoveFocusRequestedEventArgs) uintptr {
+		// Without functions, the focus cycles inside the page.
+		if len(h.webview.onFocusLeave) == 0 {
+			return 0
+		}
+
+		var reason uint32
+		syscall.Syscall(args.VTBL.GetReason, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&reason)), 0)
+		syscall.Syscall(args.VTBL.PutHandled, 2, uintptr(unsafe.Pointer(args)), 1, 0)
+
+		for _, f := range h.webview.onFocusLeave {
+			f(FocusReason(reason))
+		}
+		return 0
+	}),
+}
+
+// scriptHandler implements ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler.
+type scriptHandler struct {
+	wincom.ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler
+}
+
+var scriptHandlerVTBL = &wincom.ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *scriptHandler, _ uintptr, _ *uint16) uintptr {
+		pending.Delete(h)
+		return 0
+	}),
+}
+
+// evalHandler implements ICoreWebView2ExecuteScriptCompletedHandler.
+type evalHandler struct {
+	wincom.ICoreWebView2ExecuteScriptCompletedHandler
+}
+
+var evalHandlerVTBL = &wincom.ICoreWebView2ExecuteScriptCompletedHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *evalHandler, _ uintptr, _ *uint16) uintptr {
+		pending.Delete(h)
+		return 0
+	}),
+}
+
+// messageReceivedHandler implements ICoreWebView2WebMessageReceivedEventHandler.
+type messageReceivedHandler struct {
+	wincom.ICoreWebView2WebMessageReceivedEventHandler
+	webview *webview
+}
+
+var messageReceivedHandlerVTBL = &wincom.ICoreWebView2WebMessageReceivedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *messageReceivedHandler, _ *wincom.ICoreWebView2, args *wincom.ICoreWebView2WebMessageReceivedEventArgs) uintptr {
+		// It fails if the message isn't a string, such messages aren't sent by gowebview.
+		var s *uint16
+		if res, _, _ := syscall.Syscall(args.VTBL.TryGetWebMessageAsString, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&s)), 0); res != 0 || s == nil {
+			return 0
+		}
+		defer windows.CoTaskMemFree(unsafe.Pointer(s))
+
+		var source *uint16
+		syscall.Syscall(args.VTBL.GetSource, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&source)), 0)
+		if source != nil {
+			defer windows.CoTaskMemFree(unsafe.Pointer(source))
+		}
+
+		h.webview.message(windows.UTF16PtrToString(source), windows.UTF16PtrToString(s))
+		return 0
+	}),
+}
+
+// pending keeps the handlers alive while they are used by the browser, since the browser holds a pointer which isn't
+// visible to the garbage collector.
+var pending sync.Map
+
+// handlers keeps the event handlers alive until they are removed, it's kinda of `map[handler]bool`.
+var handlers sync.Map
+
 // watchlist is kinda of `map[hwnd]*webview
 var watchlist sync.Map
 
@@ -276,8 +1374,23 @@ func watch(hwnd w32.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 		w.updateSize(true)
 	case w32.WM_ERASEBKGND:
 		return 1
-	case w32.WM_DESTROY, w32.WM_CLOSE:
-		w.Destroy()
+	case w32.WM_MOVE:
+		w.notifyMoved()
+	case w32.WM_NCCALCSIZE:
+		if wParam != 0 && w.config.WindowConfig.Style.Has(StyleFrameless) {
+			return framelessSize(hwnd, lParam)
+		}
+	case w32.WM_COMMAND:
+		// The HIWORD of wParam is zero and lParam is zero for the menus, the LOWORD is the ID of the item.
+		if wParam>>16 == 0 && lParam == 0 {
+			w.menuItemClicked(w.view.menu.Find(int(wParam & 0xFFFF)))
+			return 0
+		}
+	case w32.WM_CLOSE:
+		w.requestClose()
+		return 0
+	case w32.WM_DESTROY:
+		w.close(ErrWindowClosed)
 	case w32.WM_PAINT:
 		p := new(w32.PAINTSTRUCT)
 		w32.BeginPaint(hwnd, p)
@@ -286,16 +1399,35 @@ func watch(hwnd w32.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 		mm := (*w32.MINMAXINFO)(unsafe.Pointer(lParam))
 		if w.view.min.X > 0 || w.view.min.Y > 0 {
 			mm.PtMinTrackSize = w32.POINT{
-				X: int32(w.view.min.X),
-				Y: int32(w.view.min.Y),
+				X: int32(w.physical(w.view.min.X)),
+				Y: int32(w.physical(w.view.min.Y)),
 			}
 		}
 		if w.view.max.X > 0 || w.view.max.Y > 0 {
 			mm.PtMaxTrackSize = w32.POINT{
-				X: int32(w.view.max.X),
-				Y: int32(w.view.max.Y),
+				X: int32(w.physical(w.view.max.X)),
+				Y: int32(w.physical(w.view.max.Y)),
 			}
 		}
+	case wmDpiChanged:
+		// The new DPI is the LOWORD of wParam, and the lParam is the suggested size and position.
+		w.view.scale = float64(wParam&0xFFFF) / 96
+
+		r := *(**w32.RECT)(unsafe.Pointer(&lParam))
+		w32.SetWindowPos(hwnd, 0, int(r.Left), int(r.Top), int(r.Right-r.Left), int(r.Bottom-r.Top), w32.SWP_NOZORDER|w32.SWP_NOACTIVATE)
+		w.updateSize(true)
+		w.updateIcon()
+		return 0
+	case w32.WM_SETTINGCHANGE:
+		// The lParam is "ImmersiveColorSet" when the user changes the theme.
+		if w.config.WindowConfig.Theme == ThemeSystem && lParam != 0 && windows.UTF16PtrToString(*(**uint16)(unsafe.Pointer(&lParam))) == "ImmersiveColorSet" {
+			w.applyTheme()
+			w32.SetWindowPos(hwnd, 0, 0, 0, 0, 0, w32.SWP_NOMOVE|w32.SWP_NOSIZE|w32.SWP_NOZORDER|w32.SWP_NOACTIVATE|w32.SWP_FRAMECHANGED)
+		}
+	default:
+		if msg == taskbarButtonCreated() {
+			w.updateTaskbar()
+		}
 	}
 
 	return w32.DefWindowProc(hwnd, msg, wParam, lParam)
@@ -332,13 +1464,43 @@ func (w *webview) createWindow() error {
 		}
 	}
 
+	if w.view.parent = w32.HWND(w.config.WindowConfig.Window); w.view.parent != 0 {
+		return w.createChild()
+	}
+
+	x, y := w32.CW_USEDEFAULT, w32.CW_USEDEFAULT
+	if p := w.config.WindowConfig.Position; p != nil {
+		x, y = int(p.X), int(p.Y)
+	}
+
+	// The window is created on the monitor of the position, or the primary monitor, so it uses the scale of
+	// that monitor to convert the logical size.
+	monitor := w32.MonitorFromPoint(0, 0, w32.MONITOR_DEFAULTTOPRIMARY)
+	if p := w.config.WindowConfig.Position; p != nil {
+		monitor = w32.MonitorFromPoint(int(p.X), int(p.Y), w32.MONITOR_DEFAULTTONEAREST)
+	}
+
+	w.view.scale = monitorScale(monitor)
+	width, height := w.physical(w.config.WindowConfig.Size.X), w.physical(w.config.WindowConfig.Size.Y)
+
+	// The saved state is in pixels.
+	if state := restoreState(w.config.WindowConfig.StateStore, screens()); state != nil {
+		x, y = int(state.Bounds.X), int(state.Bounds.Y)
+		width, height = int(state.Bounds.Width), int(state.Bounds.Height)
+		if w.config.WindowConfig.Visibility == VisibilityDefault {
+			w.config.WindowConfig.Visibility = state.Visibility
+		}
+	}
+
+	style, exStyle := windowStyle(w.config.WindowConfig.Style)
+
 	w.view.window = w32.CreateWindowEx(
-		w32.CS_HREDRAW|w32.CS_VREDRAW|w32.CS_OWNDC,
+		exStyle,
 		windows.StringToUTF16Ptr("webview"),
 		windows.StringToUTF16Ptr(""),
-		uint(w32.WS_OVERLAPPEDWINDOW),
-		w32.CW_USEDEFAULT, w32.CW_USEDEFAULT,
-		int(w.config.WindowConfig.Size.X), int(w.config.WindowConfig.Size.Y),
+		style,
+		x, y,
+		width, height,
 		0,
 		0,
 		w.view.instance,
@@ -348,47 +1510,21 @@ func (w *webview) createWindow() error {
 		return errors.New("CreateWindowEx failed")
 	}
 
-	w.SetVisibility(w.config.WindowConfig.Visibility)
-	w32.SetForegroundWindow(w.view.window)
-	w32.SetFocus(w.view.window)
-	w32.UpdateWindow(w.view.window)
-
-	return nil
-}
-
-func (w *webview) controllerCompletedHandler() uintptr {
-	h := &wincom.ICoreWebView2CreateCoreWebView2ControllerCompletedHandler{
-		VTBL: &wincom.ICoreWebView2CreateCoreWebView2ControllerCompletedHandlerVTBL{
-			Invoke: windows.NewCallback(func(i *wincom.ICoreWebView2CreateCoreWebView2ControllerCompletedHandler, p uintptr, createdController *wincom.ICoreWebView2Controller) uintptr {
-
-				syscall.Syscall(createdController.VTBL.AddRef, 1, uintptr(unsafe.Pointer(createdController)), 0, 0)
-				w.browser.controller = createdController
-
-				createdWebView2 := new(wincom.ICoreWebView2)
+	w.view.scale = windowScale(w.view.window)
 
-				syscall.Syscall(createdController.VTBL.GetCoreWebView2, 2, uintptr(unsafe.Pointer(createdController)), uintptr(unsafe.Pointer(&createdWebView2)), 0)
-				w.browser.webview = createdWebView2
+	if w.config.WindowConfig.Style.Has(StyleTransparent) {
+		transparent(w.view.window)
+	}
 
-				syscall.Syscall(w.browser.webview.VTBL.AddRef, 1, uintptr(unsafe.Pointer(w.browser.webview)), 0, 0)
-				w.done <- true
+	// The title bar isn't repainted when the theme changes, so it's applied before the window is visible.
+	w.applyTheme()
 
-				return 0
-			}),
-		},
+	w.setVisibility(w.config.WindowConfig.Visibility)
+	if w.config.WindowConfig.Visibility != VisibilityHidden {
+		w32.SetForegroundWindow(w.view.window)
+		w32.SetFocus(w.view.window)
 	}
-	h.VTBL.BasicVTBL = wincom.NewBasicVTBL(&h.Basic)
-	return uintptr(unsafe.Pointer(h))
-}
+	w32.UpdateWindow(w.view.window)
 
-func (w *webview) environmentCompletedHandler() uintptr {
-	h := &wincom.ICoreWebView2CreateCoreWebView2EnvironmentCompletedHandler{
-		VTBL: &wincom.ICoreWebView2CreateCoreWebView2EnvironmentCompletedHandlerVTBL{
-			Invoke: windows.NewCallback(func(i uintptr, p uintptr, createdEnvironment *wincom.ICoreWebView2Environment) uintptr {
-				syscall.Syscall(createdEnvironment.VTBL.CreateCoreWebView2Controller, 3, uintptr(unsafe.Pointer(createdEnvironment)), uintptr(w.view.window), w.controllerCompletedHandler())
-				return 0
-			}),
-		},
-	}
-	h.VTBL.BasicVTBL = wincom.NewBasicVTBL(&h.Basic)
-	return uintptr(unsafe.Pointer(h))
+	return nil
 }
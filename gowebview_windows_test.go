@@ -104,3 +104,102 @@ func TestNewConfig(t *testing.T) {
 	w.SetURL(`https://google.com`)
 	w.Run()
 }
+
+func TestRuntimeStatus(t *testing.T) {
+	status, err := RuntimeStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !status.Installed {
+		t.Skip("WebView2 runtime isn't installed")
+	}
+
+	if status.Version == "" {
+		t.Error("the version of the installed runtime is empty")
+	}
+}
+
+func TestClipboardOrigin(t *testing.T) {
+	config := &Config{
+		URL:          "https://app.example.com/index.html",
+		WindowConfig: &WindowConfig{ClipboardOrigins: []string{"https://other.example.com"}},
+	}
+
+	for _, test := range []struct {
+		source string
+		want   bool
+	}{
+		{"https://app.example.com/page?q=1", true},
+		{"HTTPS://APP.EXAMPLE.COM/", true},
+		{"https://other.example.com/", true},
+		{"http://app.example.com/", false},
+		{"https://app.example.com:8080/", false},
+		{"https://evil.example.com/", false},
+		{"data:text/html,<p>", false},
+		{"", false},
+	} {
+		if got := clipboardOrigin(test.source, config); got != test.want {
+			t.Errorf("clipboardOrigin(%q) = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
+
+func TestEphemeralProfile(t *testing.T) {
+	p, err := newEphemeralProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The thread of the App holds the profile, while the windows come and go.
+	thread, err := p.acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := p.acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	view()
+	p.wait()
+
+	if _, err := os.Stat(p.Path()); err != nil {
+		t.Fatalf("the profile was removed while the App is running: %v", err)
+	}
+
+	view, err = p.acquire()
+	if err != nil {
+		t.Fatalf("the next window can't use the profile: %v", err)
+	}
+	view()
+
+	thread()
+	p.wait()
+
+	if _, err := os.Stat(p.Path()); !os.IsNotExist(err) {
+		t.Errorf("the profile wasn't removed after the App is terminated: %v", err)
+	}
+
+	if _, err := p.acquire(); err != ErrProfileRemoved {
+		t.Errorf("acquire() = %v, want ErrProfileRemoved", err)
+	}
+}
+
+func TestRecentInput(t *testing.T) {
+	for _, test := range []struct {
+		now, input uint32
+		want       bool
+	}{
+		{10000, 10000, true},
+		{10000, 6000, true},
+		{10000, 5000, true},
+		{10000, 4999, false},
+		{1000, ^uint32(0) - 999, true},
+		{1000, ^uint32(0) - 4999, false},
+	} {
+		if got := recentInput(test.now, test.input); got != test.want {
+			t.Errorf("recentInput(%d, %d) = %v, want %v", test.now, test.input, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"errors"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	setWinEventHook = user32.NewProc("SetWinEventHook")
+	unhookWinEvent  = user32.NewProc("UnhookWinEvent")
+	getAncestor     = user32.NewProc("GetAncestor")
+)
+
+// eventObjectLocationChange is the EVENT_OBJECT_LOCATIONCHANGE, sent when a window moves or resizes.
+const eventObjectLocationChange = 0x800B
+
+// hooks is kinda of `map[hook]*webview`.
+var hooks sync.Map
+
+// locationChanged notifies the browser when the top-level window of the parent moves. The OBJID_WINDOW is zero, other
+// objects (such as the caret) are ignored.
+var locationChanged = windows.NewCallback(func(hook, _ uintptr, hwnd w32.HWND, object, _, _, _ uintptr) uintptr {
+	ww, ok := hooks.Load(hook)
+	if !ok || int32(object) != 0 {
+		return 0
+	}
+
+	w := ww.(*webview)
+
+	// GA_ROOT is 2.
+	if root, _, _ := getAncestor.Call(uintptr(w.view.window), 2); w32.HWND(root) == hwnd {
+		w.notifyMoved()
+	}
+	return 0
+})
+
+// createChild creates the window as a child of the WindowConfig.Window, which usually belongs to another thread, such
+// as the UI thread of the host application. The position is relative to the client area of the parent. It must be
+// called from the UI thread.
+func (w *webview) createChild() error {
+	if !w32.IsWindow(w.view.parent) {
+		return errors.New("WindowConfig.Window is not a valid window")
+	}
+
+	w.view.scale = windowScale(w.view.parent)
+
+	var x, y int
+	if p := w.config.WindowConfig.Position; p != nil {
+		x, y = int(p.X), int(p.Y)
+	}
+
+	w.view.window = w32.CreateWindowEx(
+		// Without WS_EX_NOPARENTNOTIFY, the WM_PARENTNOTIFY is sent synchronously to the parent, which belongs to
+		// another thread, and it can block if the parent is waiting for the WebView.
+		w32.WS_EX_NOPARENTNOTIFY,
+		windows.StringToUTF16Ptr("webview"),
+		windows.StringToUTF16Ptr(""),
+		w32.WS_CHILD|w32.WS_CLIPCHILDREN|w32.WS_CLIPSIBLINGS,
+		x, y,
+		w.physical(w.config.WindowConfig.Size.X), w.physical(w.config.WindowConfig.Size.Y),
+		w.view.parent,
+		0,
+		w.view.instance,
+		nil,
+	)
+	if w.view.window == 0 {
+		return errors.New("CreateWindowEx failed")
+	}
+
+	// The WM_MOVE is only received when the window moves inside the parent, the events of the parent are received
+	// using the accessibility hooks, since it belongs to another thread. WINEVENT_OUTOFCONTEXT is 0.
+	thread, process := w32.GetWindowThreadProcessId(w.view.parent)
+	hook, _, _ := setWinEventHook.Call(eventObjectLocationChange, eventObjectLocationChange, 0, locationChanged, uintptr(process), uintptr(thread), 0)
+	if hook != 0 {
+		w.view.hook = hook
+		hooks.Store(hook, w)
+	}
+
+	w.setVisibility(w.config.WindowConfig.Visibility)
+	return nil
+}
+
+// unhook removes the hook of createChild. It must be called from the UI thread.
+func (w *webview) unhook() {
+	if w.view.hook == 0 {
+		return
+	}
+
+	unhookWinEvent.Call(w.view.hook)
+	hooks.Delete(w.view.hook)
+	w.view.hook = 0
+}
+
+// notifyMoved notifies the browser that the window moved on the screen, so the popups (such as the <select>) are
+// displayed at the correct position. It must be called from the UI thread.
+func (w *webview) notifyMoved() {
+	if w.browser.controller == nil {
+		return
+	}
+
+	syscall.Syscall(w.browser.controller.VTBL.NotifyParentWindowPositionChanged, 1, uintptr(unsafe.Pointer(w.browser.controller)), 0, 0)
+}
+
+func (w *webview) Focus(reason FocusReason) {
+	w.thread.dispatch(func() {
+		if w.browser.controller == nil {
+			return
+		}
+
+		// The FocusReason has the same values of COREWEBVIEW2_MOVE_FOCUS_REASON.
+		syscall.Syscall(w.browser.controller.VTBL.MoveFocus, 2, uintptr(unsafe.Pointer(w.browser.controller)), uintptr(reason), 0)
+	})
+}
+
+func (w *webview) OnFocusLeave(f func(reason FocusReason)) {
+	w.thread.dispatch(func() {
+		w.onFocusLeave = append(w.onFocusLeave, f)
+	})
+}
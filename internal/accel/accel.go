@@ -0,0 +1,129 @@
+// Package accel parses the keyboard shortcuts, such as "Ctrl+Shift+S", used by the menus and the shortcuts of the
+// window. It doesn't depend on the platform, each backend converts the native key codes into Chord.
+package accel
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned when the shortcut can't be parsed.
+var ErrInvalid = errors.New("invalid shortcut")
+
+// Modifiers are the modifier keys, which are pressed with the Key.
+type Modifiers uint8
+
+const (
+	Ctrl Modifiers = 1 << iota
+	Alt
+	Shift
+	// Super is the Windows key, or the Command key on macOS.
+	Super
+)
+
+// Key is the name of the key, such as "A", "F5" or "PageUp". See Keys.
+type Key string
+
+// Chord is the combination of modifiers and one key.
+type Chord struct {
+	Modifiers Modifiers
+	Key       Key
+}
+
+// Keys are the names of the keys, other than the letters, digits and function keys.
+var Keys = []Key{
+	"Enter", "Escape", "Tab", "Space", "Backspace", "Delete", "Insert", "Home", "End", "PageUp", "PageDown",
+	"Up", "Down", "Left", "Right", "Plus", "Minus", "Equal", "Comma", "Period", "Slash", "Backslash", "Semicolon",
+	"Quote", "BracketLeft", "BracketRight", "Backquote",
+}
+
+// aliases are the alternative names of the keys, in lower case.
+var aliases = map[string]Key{
+	"return": "Enter", "esc": "Escape", "del": "Delete", "ins": "Insert", "pgup": "PageUp", "pgdn": "PageDown",
+	"+": "Plus", "-": "Minus", "=": "Equal", ",": "Comma", ".": "Period", "/": "Slash", "\\": "Backslash",
+	";": "Semicolon", "'": "Quote", "[": "BracketLeft", "]": "BracketRight", "`": "Backquote",
+}
+
+var modifiers = map[string]Modifiers{
+	"ctrl": Ctrl, "control": Ctrl, "cmdorctrl": Ctrl, "commandorcontrol": Ctrl,
+	"alt": Alt, "option": Alt,
+	"shift": Shift,
+	"super": Super, "meta": Super, "win": Super, "cmd": Super, "command": Super,
+}
+
+var names = [...]struct {
+	modifier Modifiers
+	name     string
+}{{Ctrl, "Ctrl"}, {Alt, "Alt"}, {Shift, "Shift"}, {Super, "Super"}}
+
+// Parse parses the shortcut, such as "Ctrl+Shift+S". The names are case-insensitive, and the plus key is written as
+// "Ctrl++" or "Ctrl+Plus". The "CmdOrCtrl" is the same of "Ctrl", since macOS isn't supported.
+func Parse(s string) (c Chord, err error) {
+	s = strings.TrimSpace(s)
+
+	var key string
+	switch i := strings.LastIndexByte(s, '+'); {
+	case s == "+":
+		key, s = s, ""
+	case strings.HasSuffix(s, "++"):
+		key, s = "+", s[:len(s)-2]
+	case i < 0:
+		key, s = s, ""
+	default:
+		key, s = s[i+1:], s[:i]
+	}
+
+	if s != "" {
+		for _, p := range strings.Split(s, "+") {
+			m, ok := modifiers[strings.ToLower(strings.TrimSpace(p))]
+			if !ok || c.Modifiers&m != 0 {
+				return Chord{}, ErrInvalid
+			}
+			c.Modifiers |= m
+		}
+	}
+
+	if c.Key = lookup(strings.TrimSpace(key)); c.Key == "" {
+		return Chord{}, ErrInvalid
+	}
+
+	return c, nil
+}
+
+// lookup returns the canonical name of the key, or an empty Key if it's unknown.
+func lookup(s string) Key {
+	lower := strings.ToLower(s)
+	if k, ok := aliases[lower]; ok {
+		return k
+	}
+
+	if len(s) == 1 && (s[0] >= '0' && s[0] <= '9' || lower[0] >= 'a' && lower[0] <= 'z') {
+		return Key(strings.ToUpper(s))
+	}
+
+	if len(lower) > 1 && lower[0] == 'f' {
+		if n, err := strconv.Atoi(lower[1:]); err == nil && n >= 1 && n <= 24 && lower[1] != '0' {
+			return Key("F" + lower[1:])
+		}
+	}
+
+	for _, k := range Keys {
+		if strings.ToLower(string(k)) == lower {
+			return k
+		}
+	}
+
+	return ""
+}
+
+// String returns the canonical form of the chord, such as "Ctrl+Shift+S", which can be parsed again.
+func (c Chord) String() string {
+	var s string
+	for _, n := range names {
+		if c.Modifiers&n.modifier != 0 {
+			s += n.name + "+"
+		}
+	}
+	return s + string(c.Key)
+}
@@ -0,0 +1,71 @@
+package accel
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Chord
+	}{
+		{input: "Ctrl+Shift+S", expected: Chord{Modifiers: Ctrl | Shift, Key: "S"}},
+		{input: "ctrl+shift+s", expected: Chord{Modifiers: Ctrl | Shift, Key: "S"}},
+		{input: " Control + Alt + Delete ", expected: Chord{Modifiers: Ctrl | Alt, Key: "Delete"}},
+		{input: "CmdOrCtrl+Q", expected: Chord{Modifiers: Ctrl, Key: "Q"}},
+		{input: "Super+Space", expected: Chord{Modifiers: Super, Key: "Space"}},
+		{input: "F5", expected: Chord{Key: "F5"}},
+		{input: "Alt+F24", expected: Chord{Modifiers: Alt, Key: "F24"}},
+		{input: "Ctrl++", expected: Chord{Modifiers: Ctrl, Key: "Plus"}},
+		{input: "Ctrl+Plus", expected: Chord{Modifiers: Ctrl, Key: "Plus"}},
+		{input: "+", expected: Chord{Key: "Plus"}},
+		{input: "Ctrl+-", expected: Chord{Modifiers: Ctrl, Key: "Minus"}},
+		{input: "Ctrl+=", expected: Chord{Modifiers: Ctrl, Key: "Equal"}},
+		{input: "Esc", expected: Chord{Key: "Escape"}},
+		{input: "Shift+pgdn", expected: Chord{Modifiers: Shift, Key: "PageDown"}},
+		{input: "Ctrl+1", expected: Chord{Modifiers: Ctrl, Key: "1"}},
+		{input: "f", expected: Chord{Key: "F"}},
+	}
+
+	for _, tt := range tests {
+		c, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.input, err)
+			continue
+		}
+
+		if c != tt.expected {
+			t.Errorf("expected %v for %q, got %v", tt.expected, tt.input, c)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{"", "Ctrl", "Ctrl+", "Ctrl+Ctrl+S", "Hyper+S", "Ctrl+S+D", "F0", "F25", "F01", "Ctrl+Shift", "Ctrl++S", "Ctrl+AB"} {
+		if _, err := Parse(s); err != ErrInvalid {
+			t.Errorf("expected ErrInvalid for %q, got %v", s, err)
+		}
+	}
+}
+
+func TestChord_String(t *testing.T) {
+	tests := []struct {
+		chord    Chord
+		expected string
+	}{
+		{chord: Chord{Modifiers: Shift | Ctrl, Key: "S"}, expected: "Ctrl+Shift+S"},
+		{chord: Chord{Modifiers: Super | Alt, Key: "Plus"}, expected: "Alt+Super+Plus"},
+		{chord: Chord{Key: "F5"}, expected: "F5"},
+	}
+
+	for _, tt := range tests {
+		if s := tt.chord.String(); s != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, s)
+		}
+
+		c, err := Parse(tt.chord.String())
+		if err != nil || c != tt.chord {
+			t.Errorf("expected %v after parsing %q, got %v (%v)", tt.chord, tt.chord.String(), c, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package accel
+
+// Group is a set of accelerators of the browser, such as the ones which reload the page.
+type Group int
+
+const (
+	Reload Group = 1 << iota
+	Print
+	Find
+	Zoom
+	DevTools
+)
+
+var groups = []struct {
+	group  Group
+	chords []Chord
+}{
+	{Reload, []Chord{{Key: "F5"}, {Modifiers: Ctrl, Key: "F5"}, {Modifiers: Shift, Key: "F5"}, {Modifiers: Ctrl, Key: "R"}, {Modifiers: Ctrl | Shift, Key: "R"}}},
+	{Print, []Chord{{Modifiers: Ctrl, Key: "P"}, {Modifiers: Ctrl | Shift, Key: "P"}}},
+	{Find, []Chord{{Modifiers: Ctrl, Key: "F"}, {Key: "F3"}, {Modifiers: Shift, Key: "F3"}, {Modifiers: Ctrl, Key: "G"}, {Modifiers: Ctrl | Shift, Key: "G"}}},
+	{Zoom, []Chord{{Modifiers: Ctrl, Key: "Plus"}, {Modifiers: Ctrl | Shift, Key: "Plus"}, {Modifiers: Ctrl, Key: "Equal"}, {Modifiers: Ctrl, Key: "Minus"}, {Modifiers: Ctrl, Key: "0"}}},
+	{DevTools, []Chord{{Key: "F12"}, {Modifiers: Ctrl | Shift, Key: "I"}, {Modifiers: Ctrl | Shift, Key: "J"}, {Modifiers: Ctrl | Shift, Key: "C"}}},
+}
+
+// Chords returns the accelerators of the groups.
+func (g Group) Chords() (chords []Chord) {
+	for _, v := range groups {
+		if g&v.group != 0 {
+			chords = append(chords, v.chords...)
+		}
+	}
+	return chords
+}
+
+// Table matches the chords pressed by the user with the registered functions and the blocked accelerators. The zero
+// value is an empty Table. It isn't safe for concurrent use.
+type Table struct {
+	functions map[Chord]func()
+	blocked   map[Chord]bool
+}
+
+// Register adds the function of the chord, replacing the previous one. The nil function removes it.
+func (t *Table) Register(c Chord, f func()) {
+	if f == nil {
+		delete(t.functions, c)
+		return
+	}
+
+	if t.functions == nil {
+		t.functions = make(map[Chord]func())
+	}
+	t.functions[c] = f
+}
+
+// Block prevents the browser from handling the accelerators of the groups.
+func (t *Table) Block(g Group) {
+	if t.blocked == nil {
+		t.blocked = make(map[Chord]bool)
+	}
+
+	for _, c := range g.Chords() {
+		t.blocked[c] = true
+	}
+}
+
+// Match returns the function of the chord, if any. The handled is true if the browser must ignore the chord, since
+// it's registered or blocked. The "Plus" also matches the "Equal", since they are the same key on most keyboards.
+func (t *Table) Match(c Chord) (f func(), handled bool) {
+	if f, ok := t.functions[c]; ok {
+		return f, true
+	}
+
+	if c.Key == "Plus" {
+		if f, ok := t.functions[Chord{Modifiers: c.Modifiers, Key: "Equal"}]; ok {
+			return f, true
+		}
+	}
+
+	return nil, t.blocked[c]
+}
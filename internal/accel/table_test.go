@@ -0,0 +1,86 @@
+package accel
+
+import (
+	"testing"
+)
+
+func TestTable_Match(t *testing.T) {
+	var table Table
+	var called string
+
+	table.Register(Chord{Modifiers: Ctrl | Shift, Key: "I"}, func() { called = "inspect" })
+	table.Register(Chord{Modifiers: Ctrl, Key: "Equal"}, func() { called = "bigger" })
+	table.Register(Chord{Key: "F5"}, func() { called = "refresh" })
+	table.Block(Reload | Print)
+
+	tests := []struct {
+		chord    Chord
+		called   string
+		handled  bool
+		function bool
+	}{
+		{chord: Chord{Modifiers: Ctrl | Shift, Key: "I"}, called: "inspect", handled: true, function: true},
+		{chord: Chord{Modifiers: Ctrl, Key: "Plus"}, called: "bigger", handled: true, function: true},
+		{chord: Chord{Modifiers: Ctrl, Key: "Equal"}, called: "bigger", handled: true, function: true},
+		{chord: Chord{Key: "F5"}, called: "refresh", handled: true, function: true},
+		{chord: Chord{Modifiers: Ctrl, Key: "R"}, handled: true},
+		{chord: Chord{Modifiers: Ctrl, Key: "P"}, handled: true},
+		{chord: Chord{Modifiers: Ctrl, Key: "F"}},
+		{chord: Chord{Modifiers: Ctrl | Alt, Key: "I"}},
+	}
+
+	for _, tt := range tests {
+		called = ""
+
+		f, handled := table.Match(tt.chord)
+		if handled != tt.handled || (f != nil) != tt.function {
+			t.Errorf("%s: expected handled %v and function %v, got %v and %v", tt.chord, tt.handled, tt.function, handled, f != nil)
+			continue
+		}
+
+		if f != nil {
+			f()
+		}
+		if called != tt.called {
+			t.Errorf("%s: expected %q, got %q", tt.chord, tt.called, called)
+		}
+	}
+}
+
+func TestTable_Register_Nil(t *testing.T) {
+	var table Table
+
+	c := Chord{Modifiers: Ctrl, Key: "K"}
+	table.Register(c, func() {})
+	table.Register(c, nil)
+
+	if f, handled := table.Match(c); f != nil || handled {
+		t.Errorf("expected the chord to be removed, got %v and %v", f != nil, handled)
+	}
+
+	// The removed function doesn't unblock the accelerator.
+	table.Block(Find)
+	table.Register(Chord{Modifiers: Ctrl, Key: "F"}, func() {})
+	table.Register(Chord{Modifiers: Ctrl, Key: "F"}, nil)
+
+	if _, handled := table.Match(Chord{Modifiers: Ctrl, Key: "F"}); !handled {
+		t.Error("expected the chord to be blocked")
+	}
+}
+
+func TestGroup_Chords(t *testing.T) {
+	if chords := Group(0).Chords(); len(chords) != 0 {
+		t.Errorf("expected no chords, got %v", chords)
+	}
+
+	zoom := Zoom.Chords()
+	for _, c := range zoom {
+		if c.Modifiers&Ctrl == 0 {
+			t.Errorf("expected Ctrl on all zoom chords, got %s", c)
+		}
+	}
+
+	if all := (Reload | Print | Find | Zoom | DevTools).Chords(); len(all) != len(Reload.Chords())+len(Print.Chords())+len(Find.Chords())+len(zoom)+len(DevTools.Chords()) {
+		t.Errorf("expected all chords, got %d", len(all))
+	}
+}
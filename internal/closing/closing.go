@@ -0,0 +1,99 @@
+// Package closing aggregates the functions which decide if the window can be closed. The synchronous functions are
+// asked first, then the asynchronous ones, which reply later from any thread.
+package closing
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the time to wait for the replies of the asynchronous functions, used when Handlers.Timeout is
+// zero. The request is denied when it expires.
+const DefaultTimeout = 30 * time.Second
+
+// Handlers keeps the functions which decide if the window can be closed. It must be used from the UI thread.
+type Handlers struct {
+	Sync  []func() bool
+	Async []func(reply func(allow bool))
+
+	// Timeout is the time to wait for the replies of the Async functions, see DefaultTimeout.
+	Timeout time.Duration
+
+	pending *request
+}
+
+// request is the request waiting for the replies of the Async functions.
+type request struct {
+	waiting int
+	timer   *time.Timer
+}
+
+// Request asks all functions if the window can be closed, the closeWindow function is called once all of them allow
+// it. The dispatch function must run the given function on the UI thread, since the reply can be given from any
+// thread. It does nothing if the previous request is still waiting for replies. The request is denied if any Async
+// function doesn't reply before the Timeout, so the next request asks them again.
+func (h *Handlers) Request(dispatch func(f func()), closeWindow func()) {
+	if h.pending != nil {
+		return
+	}
+
+	for _, f := range h.Sync {
+		if !f() {
+			return
+		}
+	}
+
+	if len(h.Async) == 0 {
+		closeWindow()
+		return
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	req := &request{waiting: len(h.Async)}
+	req.timer = time.AfterFunc(timeout, func() {
+		dispatch(func() {
+			if h.pending == req {
+				h.pending = nil
+			}
+		})
+	})
+	h.pending = req
+
+	for _, f := range h.Async {
+		var once sync.Once
+		f(func(allow bool) {
+			once.Do(func() {
+				dispatch(func() {
+					if h.pending != req {
+						return
+					}
+
+					if !allow {
+						h.finish()
+						return
+					}
+
+					if req.waiting--; req.waiting == 0 {
+						h.finish()
+						closeWindow()
+					}
+				})
+			})
+		})
+	}
+}
+
+// Pending returns true if the request is waiting for the replies of the Async functions.
+func (h *Handlers) Pending() bool {
+	return h.pending != nil
+}
+
+// finish forgets the pending request.
+func (h *Handlers) finish() {
+	h.pending.timer.Stop()
+	h.pending = nil
+}
@@ -0,0 +1,163 @@
+package closing
+
+import (
+	"testing"
+	"time"
+)
+
+// queue runs the dispatched functions when run is called, like the UI thread.
+type queue []func()
+
+func (q *queue) dispatch(f func()) {
+	*q = append(*q, f)
+}
+
+func (q *queue) run() {
+	for len(*q) > 0 {
+		f := (*q)[0]
+		*q = (*q)[1:]
+		f()
+	}
+}
+
+func TestHandlers_Sync(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		replies []bool
+		closed  bool
+	}{
+		{"none", nil, true},
+		{"allow", []bool{true, true}, true},
+		{"deny", []bool{true, false}, false},
+	} {
+		var h Handlers
+		for _, allow := range test.replies {
+			allow := allow
+			h.Sync = append(h.Sync, func() bool { return allow })
+		}
+
+		var q queue
+		closed := false
+		h.Request(q.dispatch, func() { closed = true })
+		q.run()
+
+		if closed != test.closed {
+			t.Errorf("%s: expected closed %v, got %v", test.name, test.closed, closed)
+		}
+		if h.Pending() {
+			t.Errorf("%s: unexpected pending request", test.name)
+		}
+	}
+}
+
+func TestHandlers_Async(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		replies []bool
+		closed  bool
+	}{
+		{"allow", []bool{true, true}, true},
+		{"deny first", []bool{false, true}, false},
+		{"deny last", []bool{true, false}, false},
+	} {
+		var h Handlers
+		var replies []func(allow bool)
+		for range test.replies {
+			h.Async = append(h.Async, func(reply func(allow bool)) { replies = append(replies, reply) })
+		}
+
+		var q queue
+		closed := false
+		h.Request(q.dispatch, func() { closed = true })
+		if !h.Pending() {
+			t.Fatalf("%s: expected pending request", test.name)
+		}
+
+		for i, allow := range test.replies {
+			replies[i](allow)
+			replies[i](!allow) // Only the first reply counts.
+		}
+		q.run()
+
+		if closed != test.closed {
+			t.Errorf("%s: expected closed %v, got %v", test.name, test.closed, closed)
+		}
+		if h.Pending() {
+			t.Errorf("%s: unexpected pending request", test.name)
+		}
+	}
+}
+
+func TestHandlers_SyncDenySkipsAsync(t *testing.T) {
+	asked := false
+	h := Handlers{
+		Sync:  []func() bool{func() bool { return false }},
+		Async: []func(reply func(allow bool)){func(reply func(allow bool)) { asked = true }},
+	}
+
+	var q queue
+	h.Request(q.dispatch, func() { t.Error("unexpected close") })
+	q.run()
+
+	if asked {
+		t.Error("the async function was asked after the sync function denied")
+	}
+}
+
+func TestHandlers_Pending(t *testing.T) {
+	asked := 0
+	h := Handlers{
+		Async: []func(reply func(allow bool)){func(reply func(allow bool)) { asked++ }},
+	}
+
+	var q queue
+	h.Request(q.dispatch, func() {})
+	h.Request(q.dispatch, func() {})
+
+	if asked != 1 {
+		t.Errorf("expected 1 request while pending, got %d", asked)
+	}
+}
+
+func TestHandlers_Timeout(t *testing.T) {
+	var replies []func(allow bool)
+	h := Handlers{
+		Async:   []func(reply func(allow bool)){func(reply func(allow bool)) { replies = append(replies, reply) }},
+		Timeout: 10 * time.Millisecond,
+	}
+
+	dispatched := make(chan func(), 2)
+	dispatch := func(f func()) { dispatched <- f }
+
+	closed := false
+	h.Request(dispatch, func() { closed = true })
+
+	select {
+	case f := <-dispatched:
+		f()
+	case <-time.After(time.Second):
+		t.Fatal("the timeout didn't expire")
+	}
+
+	if h.Pending() {
+		t.Fatal("the request is still pending after the timeout")
+	}
+
+	// The late reply of the expired request is ignored, and the next request asks again.
+	replies[0](true)
+	(<-dispatched)()
+	if closed {
+		t.Error("the late reply closed the window")
+	}
+
+	h.Request(dispatch, func() { closed = true })
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(replies))
+	}
+
+	replies[1](true)
+	(<-dispatched)()
+	if !closed {
+		t.Error("expected closed")
+	}
+}
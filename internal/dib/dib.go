@@ -0,0 +1,148 @@
+// Package dib converts images from and to the device-independent bitmap, which is the CF_DIB format of the Windows
+// clipboard: the BITMAPINFO followed by the pixels, without the file header of the BMP.
+package dib
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"math/bits"
+)
+
+// ErrFormat is returned when the data isn't a valid DIB, or uses an unsupported compression or bit count.
+var ErrFormat = errors.New("dib: invalid format")
+
+const (
+	headerSize = 40
+
+	biRGB       = 0
+	biBitFields = 3
+)
+
+// Encode returns the image as a bottom-up DIB of 32 bits per pixel, the alpha is stored but most applications
+// ignore it.
+func Encode(img image.Image) []byte {
+	r := img.Bounds()
+	width, height := r.Dx(), r.Dy()
+
+	b := make([]byte, headerSize+width*height*4)
+	binary.LittleEndian.PutUint32(b[0:], headerSize)
+	binary.LittleEndian.PutUint32(b[4:], uint32(width))
+	binary.LittleEndian.PutUint32(b[8:], uint32(height))
+	binary.LittleEndian.PutUint16(b[12:], 1)
+	binary.LittleEndian.PutUint16(b[14:], 32)
+	binary.LittleEndian.PutUint32(b[16:], biRGB)
+	binary.LittleEndian.PutUint32(b[20:], uint32(width*height*4))
+
+	p := b[headerSize:]
+	for y := height - 1; y >= 0; y-- {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(r.Min.X+x, r.Min.Y+y)).(color.NRGBA)
+			p[0], p[1], p[2], p[3] = c.B, c.G, c.R, c.A
+			p = p[4:]
+		}
+	}
+	return b
+}
+
+// Decode reads the DIB of 24 or 32 bits per pixel, uncompressed or with bit fields. The 32 bits DIB without any
+// alpha is opaque, since the alpha is usually unused.
+func Decode(b []byte) (image.Image, error) {
+	if len(b) < headerSize {
+		return nil, ErrFormat
+	}
+
+	size := int(binary.LittleEndian.Uint32(b[0:]))
+	width := int(int32(binary.LittleEndian.Uint32(b[4:])))
+	height := int(int32(binary.LittleEndian.Uint32(b[8:])))
+	count := int(binary.LittleEndian.Uint16(b[14:]))
+	compression := binary.LittleEndian.Uint32(b[16:])
+	if size < headerSize || size > len(b) || width <= 0 || height == 0 {
+		return nil, ErrFormat
+	}
+
+	// The negative height is a top-down DIB.
+	bottomUp := height > 0
+	if !bottomUp {
+		height = -height
+	}
+
+	masks := [4]uint32{0x00FF0000, 0x0000FF00, 0x000000FF, 0}
+	offset := size
+	switch {
+	case compression == biRGB && (count == 24 || count == 32):
+	case compression == biBitFields && count == 32:
+		// The masks follow the BITMAPINFOHEADER, or are part of the BITMAPV4HEADER and BITMAPV5HEADER.
+		if size == headerSize {
+			offset += 12
+		}
+		if len(b) < headerSize+12 || len(b) < offset {
+			return nil, ErrFormat
+		}
+		for i := 0; i < 3; i++ {
+			masks[i] = binary.LittleEndian.Uint32(b[headerSize+4*i:])
+		}
+		if size >= headerSize+16 {
+			masks[3] = binary.LittleEndian.Uint32(b[headerSize+12:])
+		}
+	default:
+		return nil, ErrFormat
+	}
+
+	stride := (width*count/8 + 3) &^ 3
+	if uint64(offset)+uint64(stride)*uint64(height) > uint64(len(b)) {
+		return nil, ErrFormat
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	transparent := count == 32 && compression == biRGB
+	for y := 0; y < height; y++ {
+		row := b[offset+stride*y:]
+		if bottomUp {
+			row = b[offset+stride*(height-1-y):]
+		}
+
+		for x := 0; x < width; x++ {
+			var c color.NRGBA
+			if count == 24 {
+				c = color.NRGBA{R: row[x*3+2], G: row[x*3+1], B: row[x*3], A: 255}
+			} else {
+				v := binary.LittleEndian.Uint32(row[x*4:])
+				c = color.NRGBA{R: channel(v, masks[0]), G: channel(v, masks[1]), B: channel(v, masks[2]), A: 255}
+				if compression == biRGB {
+					c.A = uint8(v >> 24)
+				} else if masks[3] != 0 {
+					c.A = channel(v, masks[3])
+				}
+			}
+
+			if c.A != 0 {
+				transparent = false
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	if transparent {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+	}
+
+	return img, nil
+}
+
+// channel extracts the value of the mask, scaled to 8 bits.
+func channel(v, mask uint32) uint8 {
+	if mask == 0 {
+		return 0
+	}
+
+	n := bits.OnesCount32(mask)
+	v = (v & mask) >> uint(bits.TrailingZeros32(mask))
+	if n >= 8 {
+		return uint8(v >> uint(n-8))
+	}
+	return uint8(v * 255 / (1<<uint(n) - 1))
+}
@@ -0,0 +1,123 @@
+package dib
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 100), G: uint8(y * 200), B: 50, A: uint8(100 + x*50)})
+		}
+	}
+
+	b := Encode(src)
+	if len(b) != headerSize+3*2*4 {
+		t.Fatalf("unexpected length %d", len(b))
+	}
+
+	// The first row of the data is the bottom row of the image.
+	if b[headerSize+2] != 0 || b[headerSize+1] != 200 {
+		t.Errorf("expected bottom-up DIB, got %v", b[headerSize:headerSize+4])
+	}
+
+	img, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			if got, expected := img.At(x, y), src.At(x, y); got != expected {
+				t.Errorf("(%d, %d): expected %v, got %v", x, y, expected, got)
+			}
+		}
+	}
+}
+
+// header creates the BITMAPINFOHEADER.
+func header(width, height int32, count uint16, compression uint32) []byte {
+	b := make([]byte, headerSize)
+	binary.LittleEndian.PutUint32(b[0:], headerSize)
+	binary.LittleEndian.PutUint32(b[4:], uint32(width))
+	binary.LittleEndian.PutUint32(b[8:], uint32(height))
+	binary.LittleEndian.PutUint16(b[12:], 1)
+	binary.LittleEndian.PutUint16(b[14:], count)
+	binary.LittleEndian.PutUint32(b[16:], compression)
+	return b
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected []color.NRGBA
+	}{
+		{
+			// The rows are padded to 4 bytes.
+			name:     "24 bits",
+			data:     append(header(1, 2, 24, biRGB), 1, 2, 3, 0, 4, 5, 6, 0),
+			expected: []color.NRGBA{{R: 6, G: 5, B: 4, A: 255}, {R: 3, G: 2, B: 1, A: 255}},
+		},
+		{
+			name:     "top-down",
+			data:     append(header(1, -2, 24, biRGB), 1, 2, 3, 0, 4, 5, 6, 0),
+			expected: []color.NRGBA{{R: 3, G: 2, B: 1, A: 255}, {R: 6, G: 5, B: 4, A: 255}},
+		},
+		{
+			name:     "32 bits without alpha",
+			data:     append(header(1, 1, 32, biRGB), 1, 2, 3, 0),
+			expected: []color.NRGBA{{R: 3, G: 2, B: 1, A: 255}},
+		},
+		{
+			name: "bit fields",
+			data: append(header(1, 1, 32, biBitFields),
+				0, 0, 0, 0xFF, // Red
+				0, 0, 0xFF, 0, // Green
+				0, 0xFF, 0, 0, // Blue
+				1, 2, 3, 4,
+			),
+			expected: []color.NRGBA{{R: 4, G: 3, B: 2, A: 255}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Decode(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for y, expected := range tt.expected {
+				if got := img.At(0, y); got != expected {
+					t.Errorf("row %d: expected %v, got %v", y, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "short", data: make([]byte, 10)},
+		{name: "zero width", data: header(0, 1, 32, biRGB)},
+		{name: "8 bits", data: append(header(1, 1, 8, biRGB), 0, 0, 0, 0)},
+		{name: "compressed", data: append(header(1, 1, 32, 1), 0, 0, 0, 0)},
+		{name: "truncated", data: append(header(2, 2, 32, biRGB), 0, 0, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.data); err != ErrFormat {
+				t.Errorf("expected ErrFormat, got %v", err)
+			}
+		})
+	}
+}
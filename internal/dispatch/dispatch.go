@@ -0,0 +1,126 @@
+// Package dispatch implements the queue of functions which must run on the UI thread. The UI thread must call Drain
+// every time it's woken up, and Close once it stops.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed is returned when the UI thread is stopped before the function runs.
+var ErrClosed = errors.New("the UI thread is closed")
+
+// Queue keeps the functions which must run on the UI thread.
+type Queue struct {
+	wake    func()
+	current func() bool
+
+	mutex  sync.Mutex
+	funcs  []func()
+	closed bool
+	done   chan struct{}
+}
+
+// New creates a Queue. The wake function must wake up the UI thread, which will call Drain. The current function
+// must return true if it's called from the UI thread. Both functions are called from any goroutine.
+func New(wake func(), current func() bool) *Queue {
+	return &Queue{
+		wake:    wake,
+		current: current,
+		done:    make(chan struct{}),
+	}
+}
+
+// Dispatch adds the function to the queue and wakes up the UI thread. It returns false if the queue is closed, in
+// that case the function will never run.
+func (q *Queue) Dispatch(f func()) bool {
+	q.mutex.Lock()
+	if q.closed {
+		q.mutex.Unlock()
+		return false
+	}
+	q.funcs = append(q.funcs, f)
+	q.mutex.Unlock()
+
+	q.wake()
+	return true
+}
+
+// DispatchSync runs the function on the UI thread and waits until it returns. If it's called from the UI thread, the
+// function runs immediately.
+//
+// If the context is done before the function starts, the function never runs and the context error is returned. If
+// the function is already running, it waits until the function returns.
+func (q *Queue) DispatchSync(ctx context.Context, f func() error) error {
+	if q.current() {
+		return f()
+	}
+
+	const (
+		waiting int32 = iota
+		started
+		cancelled
+	)
+
+	var state int32
+	result := make(chan error, 1)
+
+	ok := q.Dispatch(func() {
+		if !atomic.CompareAndSwapInt32(&state, waiting, started) {
+			return
+		}
+		result <- f()
+	})
+	if !ok {
+		return ErrClosed
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		if atomic.CompareAndSwapInt32(&state, waiting, cancelled) {
+			return ctx.Err()
+		}
+	case <-q.done:
+		if atomic.CompareAndSwapInt32(&state, waiting, cancelled) {
+			return ErrClosed
+		}
+	}
+
+	return <-result
+}
+
+// Drain runs all functions in the queue, in the same order which they were added. It must be called from the UI
+// thread.
+func (q *Queue) Drain() {
+	q.mutex.Lock()
+	funcs := q.funcs
+	q.funcs = nil
+	q.mutex.Unlock()
+
+	for _, f := range funcs {
+		f()
+	}
+}
+
+// Close discards all pending functions, any further function is ignored. It must be called when the UI thread stops.
+func (q *Queue) Close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.closed = true
+	q.funcs = nil
+	close(q.done)
+}
+
+// Done returns a channel which is closed when the queue is closed.
+func (q *Queue) Done() <-chan struct{} {
+	return q.done
+}
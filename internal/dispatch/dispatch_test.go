@@ -0,0 +1,197 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// thread is a fake UI thread, which runs the functions of the Queue on a single goroutine.
+type thread struct {
+	queue   *Queue
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	inside  int32
+}
+
+func newThread() *thread {
+	t := &thread{
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	t.queue = New(func() {
+		select {
+		case t.wake <- struct{}{}:
+		default:
+		}
+	}, func() bool {
+		return atomic.LoadInt32(&t.inside) == 1
+	})
+
+	go func() {
+		defer close(t.stopped)
+		defer t.queue.Close()
+
+		for {
+			select {
+			case <-t.wake:
+				atomic.StoreInt32(&t.inside, 1)
+				t.queue.Drain()
+				atomic.StoreInt32(&t.inside, 0)
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+
+	return t
+}
+
+func (t *thread) close() {
+	close(t.stop)
+	<-t.stopped
+}
+
+func TestQueue_Dispatch(t *testing.T) {
+	th := newThread()
+	defer th.close()
+
+	var order []int
+	for i := 0; i < 100; i++ {
+		i := i
+		th.queue.Dispatch(func() {
+			order = append(order, i)
+		})
+	}
+
+	if err := th.queue.DispatchSync(context.Background(), func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(order) != 100 {
+		t.Fatalf("expected 100 calls, got %d", len(order))
+	}
+
+	for i, v := range order {
+		if i != v {
+			t.Fatalf("unexpected order at %d: %d", i, v)
+		}
+	}
+}
+
+func TestQueue_DispatchSync(t *testing.T) {
+	th := newThread()
+	defer th.close()
+
+	expected := errors.New("expected")
+	if err := th.queue.DispatchSync(context.Background(), func() error { return expected }); err != expected {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQueue_DispatchSyncNested(t *testing.T) {
+	th := newThread()
+	defer th.close()
+
+	var called bool
+	err := th.queue.DispatchSync(context.Background(), func() error {
+		// It's already on the UI thread, so it must run immediately instead of waiting itself.
+		return th.queue.DispatchSync(context.Background(), func() error {
+			called = true
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !called {
+		t.Error("nested function wasn't called")
+	}
+}
+
+func TestQueue_DispatchSyncCancelled(t *testing.T) {
+	th := newThread()
+	defer th.close()
+
+	block := make(chan struct{})
+	th.queue.Dispatch(func() { <-block })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var called int32
+	err := th.queue.DispatchSync(ctx, func() error {
+		atomic.StoreInt32(&called, 1)
+		return nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	close(block)
+
+	if err := th.queue.DispatchSync(context.Background(), func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if atomic.LoadInt32(&called) != 0 {
+		t.Error("cancelled function was called")
+	}
+}
+
+func TestQueue_DispatchSyncRunning(t *testing.T) {
+	th := newThread()
+	defer th.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := th.queue.DispatchSync(ctx, func() error {
+		// Once the function is running, the cancellation must wait for the result.
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQueue_Close(t *testing.T) {
+	th := newThread()
+
+	block := make(chan struct{})
+	th.queue.Dispatch(func() { <-block })
+
+	result := make(chan error, 1)
+	go func() {
+		result <- th.queue.DispatchSync(context.Background(), func() error { return nil })
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(block)
+	close(th.stop)
+	<-th.stopped
+
+	select {
+	case err := <-result:
+		if err != nil && err != ErrClosed {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("DispatchSync is blocked after Close")
+	}
+
+	if th.queue.Dispatch(func() {}) {
+		t.Error("Dispatch must return false after Close")
+	}
+
+	if err := th.queue.DispatchSync(context.Background(), func() error { return nil }); err != ErrClosed {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
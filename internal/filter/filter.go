@@ -0,0 +1,165 @@
+// Package filter parses the filters of the file dialogs, such as "Images|*.png;*.jpg", and converts the accept
+// attribute of the HTML file input into filters and MIME types, used by the backends which can't use patterns.
+package filter
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrInvalid is returned when the filter can't be parsed.
+var ErrInvalid = errors.New("invalid file filter")
+
+// Filter is a named list of patterns, such as "Images" with "*.png" and "*.jpg".
+type Filter struct {
+	Name     string
+	Patterns []string
+}
+
+// types is kinda of `map[extension]MIME`, it doesn't depend on the system, unlike the mime package.
+var types = map[string]string{
+	"png": "image/png", "jpg": "image/jpeg", "jpeg": "image/jpeg", "gif": "image/gif", "webp": "image/webp",
+	"bmp": "image/bmp", "svg": "image/svg+xml", "ico": "image/x-icon", "tif": "image/tiff", "tiff": "image/tiff",
+	"avif": "image/avif", "heic": "image/heic",
+	"mp3": "audio/mpeg", "wav": "audio/wav", "ogg": "audio/ogg", "m4a": "audio/mp4", "flac": "audio/flac",
+	"aac": "audio/aac",
+	"mp4": "video/mp4", "webm": "video/webm", "mov": "video/quicktime", "mkv": "video/x-matroska",
+	"avi": "video/x-msvideo",
+	"txt": "text/plain", "csv": "text/csv", "html": "text/html", "htm": "text/html", "css": "text/css",
+	"md": "text/markdown", "js": "text/javascript",
+	"json": "application/json", "pdf": "application/pdf", "zip": "application/zip", "xml": "application/xml",
+}
+
+// Parse parses the filters, such as "Images|*.png;*.jpg|All Files|*.*". The names and the patterns alternate,
+// separated by "|", and the patterns are separated by ";". The empty name is replaced by the patterns. The empty
+// spec returns no filters.
+func Parse(spec string) ([]Filter, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(spec, "|")
+	if len(parts)%2 != 0 {
+		return nil, ErrInvalid
+	}
+
+	filters := make([]Filter, 0, len(parts)/2)
+	for i := 0; i < len(parts); i += 2 {
+		f := Filter{Name: strings.TrimSpace(parts[i])}
+		for _, p := range strings.Split(parts[i+1], ";") {
+			if p = strings.TrimSpace(p); p == "" {
+				continue
+			}
+			if strings.ContainsAny(p, `/\`) {
+				return nil, ErrInvalid
+			}
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, ErrInvalid
+			}
+			f.Patterns = append(f.Patterns, p)
+		}
+
+		if len(f.Patterns) == 0 {
+			return nil, ErrInvalid
+		}
+		if f.Name == "" {
+			f.Name = strings.Join(f.Patterns, ";")
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// Match returns true if the name of the file matches any of the patterns, ignoring the case. The "*.*" matches all
+// files, including the ones without extension.
+func (f Filter) Match(name string) bool {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.ToLower(name)
+
+	for _, p := range f.Patterns {
+		if p == "*.*" {
+			return true
+		}
+		if ok, _ := path.Match(strings.ToLower(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FromAccept converts the accept attribute of the HTML file input, such as "image/*,.pdf", into the filter. The
+// unknown MIME types are ignored. It returns false if there is no pattern, so all files are accepted.
+func FromAccept(accept string) (Filter, bool) {
+	var f Filter
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			f.Patterns = append(f.Patterns, p)
+		}
+	}
+
+	for _, t := range strings.Split(accept, ",") {
+		switch t = strings.ToLower(strings.TrimSpace(t)); {
+		case t == "":
+		case strings.HasPrefix(t, "."):
+			if len(t) > 1 && !strings.ContainsAny(t, `/\*?[`) {
+				add("*" + t)
+			}
+		case strings.HasSuffix(t, "/*"):
+			for _, ext := range extensions(func(mime string) bool { return strings.HasPrefix(mime, t[:len(t)-1]) }) {
+				add("*." + ext)
+			}
+		default:
+			for _, ext := range extensions(func(mime string) bool { return mime == t }) {
+				add("*." + ext)
+			}
+		}
+	}
+
+	if len(f.Patterns) == 0 {
+		return Filter{}, false
+	}
+	f.Name = strings.Join(f.Patterns, ";")
+	return f, true
+}
+
+// extensions returns the sorted extensions of the MIME types which match.
+func extensions(match func(mime string) bool) (exts []string) {
+	for ext, mime := range types {
+		if match(mime) {
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// MIMETypes returns the MIME types of the patterns, such as "image/png" for "*.png". It returns nil if any pattern
+// isn't a known extension, such as "*" or "report-*.txt", since it can't be represented, so all files are accepted.
+func MIMETypes(filters []Filter) (mimes []string) {
+	seen := make(map[string]bool)
+	for _, f := range filters {
+		for _, p := range f.Patterns {
+			if !strings.HasPrefix(p, "*.") {
+				return nil
+			}
+
+			mime, ok := types[strings.ToLower(p[2:])]
+			if !ok {
+				return nil
+			}
+
+			if !seen[mime] {
+				seen[mime] = true
+				mimes = append(mimes, mime)
+			}
+		}
+	}
+	return mimes
+}
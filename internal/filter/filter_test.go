@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Filter
+	}{
+		{input: "", expected: nil},
+		{input: "Images|*.png;*.jpg", expected: []Filter{{Name: "Images", Patterns: []string{"*.png", "*.jpg"}}}},
+		{input: " Images | *.png ; *.jpg ;| All Files |*.*", expected: []Filter{
+			{Name: "Images", Patterns: []string{"*.png", "*.jpg"}},
+			{Name: "All Files", Patterns: []string{"*.*"}},
+		}},
+		{input: "|report-*.csv", expected: []Filter{{Name: "report-*.csv", Patterns: []string{"report-*.csv"}}}},
+	}
+
+	for _, tt := range tests {
+		filters, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(filters, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.expected, filters)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"Images", "Images|", "Images|;", "Images|*.png|Text", "Images|[.png", "Images|dir/*.png", `Images|dir\*.png`} {
+		if _, err := Parse(input); err != ErrInvalid {
+			t.Errorf("%q: expected ErrInvalid, got %v", input, err)
+		}
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	f := Filter{Patterns: []string{"*.png", "report-*.csv"}}
+
+	for name, expected := range map[string]bool{
+		"image.png":               true,
+		"IMAGE.PNG":               true,
+		`C:\Users\image.png`:      true,
+		"/home/user/report-1.csv": true,
+		"report.csv":              false,
+		"image.png.txt":           false,
+		"png":                     false,
+	} {
+		if f.Match(name) != expected {
+			t.Errorf("%q: expected %v", name, expected)
+		}
+	}
+
+	if all := (Filter{Patterns: []string{"*.*"}}); !all.Match("Makefile") {
+		t.Error("expected *.* to match files without extension")
+	}
+}
+
+func TestFromAccept(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: ".pdf", expected: []string{"*.pdf"}},
+		{input: "image/jpeg, .PNG", expected: []string{"*.jpeg", "*.jpg", "*.png"}},
+		{input: "audio/*", expected: []string{"*.aac", "*.flac", "*.m4a", "*.mp3", "*.ogg", "*.wav"}},
+		{input: "image/png,.png", expected: []string{"*.png"}},
+		{input: "application/x-unknown,.csv", expected: []string{"*.csv"}},
+	}
+
+	for _, tt := range tests {
+		f, ok := FromAccept(tt.input)
+		if !ok {
+			t.Errorf("%q: expected a filter", tt.input)
+			continue
+		}
+		if !reflect.DeepEqual(f.Patterns, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.expected, f.Patterns)
+		}
+	}
+
+	for _, input := range []string{"", " , ", "application/x-unknown", ".", "*/*"} {
+		if _, ok := FromAccept(input); ok {
+			t.Errorf("%q: expected no filter", input)
+		}
+	}
+}
+
+func TestMIMETypes(t *testing.T) {
+	filters := []Filter{
+		{Patterns: []string{"*.png", "*.JPG", "*.jpeg"}},
+		{Patterns: []string{"*.pdf"}},
+	}
+	if mimes := MIMETypes(filters); !reflect.DeepEqual(mimes, []string{"image/png", "image/jpeg", "application/pdf"}) {
+		t.Errorf("unexpected MIME types: %v", mimes)
+	}
+
+	for _, p := range []string{"*", "*.*", "*.unknown", "report-*.csv"} {
+		if mimes := MIMETypes([]Filter{{Patterns: []string{"*.png", p}}}); mimes != nil {
+			t.Errorf("%q: expected nil, got %v", p, mimes)
+		}
+	}
+
+	if mimes := MIMETypes(nil); mimes != nil {
+		t.Errorf("expected nil, got %v", mimes)
+	}
+}
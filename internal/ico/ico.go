@@ -0,0 +1,172 @@
+// Package ico converts images to the ICO format, used by the icons of the windows. Each size is stored as PNG, which
+// is supported since Windows Vista and keeps the alpha channel.
+package ico
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// DefaultSizes are the sizes used when none is given, they cover the common DPI scales of the title bar, taskbar and
+// Alt+Tab.
+var DefaultSizes = []int{16, 20, 24, 32, 40, 48, 64, 256}
+
+var (
+	// ErrSize is returned when the size is not between 1 and 256.
+	ErrSize = errors.New("ico: invalid size")
+
+	// ErrFormat is returned when the data isn't a valid ICO.
+	ErrFormat = errors.New("ico: invalid format")
+)
+
+const (
+	headerSize = 6
+	entrySize  = 16
+)
+
+// Encode writes the image as ICO, the image is resized to each size. Images which aren't square are centered with a
+// transparent background. If no size is given, it uses the DefaultSizes.
+func Encode(w io.Writer, img image.Image, sizes ...int) error {
+	if len(sizes) == 0 {
+		sizes = DefaultSizes
+	}
+
+	images := make([][]byte, len(sizes))
+	for i, size := range sizes {
+		if size < 1 || size > 256 {
+			return ErrSize
+		}
+
+		b := new(bytes.Buffer)
+		if err := png.Encode(b, Resize(img, size)); err != nil {
+			return err
+		}
+		images[i] = b.Bytes()
+	}
+
+	b := new(bytes.Buffer)
+	binary.Write(b, binary.LittleEndian, [3]uint16{0, 1, uint16(len(sizes))})
+
+	offset := headerSize + entrySize*len(sizes)
+	for i, size := range sizes {
+		// The width and height are stored in one byte, where 0 means 256.
+		binary.Write(b, binary.LittleEndian, struct {
+			Width, Height, Colors, Reserved uint8
+			Planes, BitCount                uint16
+			Length, Offset                  uint32
+		}{
+			Width:    uint8(size),
+			Height:   uint8(size),
+			Planes:   1,
+			BitCount: 32,
+			Length:   uint32(len(images[i])),
+			Offset:   uint32(offset),
+		})
+		offset += len(images[i])
+	}
+
+	for _, data := range images {
+		b.Write(data)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Lookup returns the image data (PNG or BMP) of the ICO which best fits the size: the smallest which isn't smaller
+// than the size, or the largest one.
+func Lookup(data []byte, size int) ([]byte, error) {
+	if len(data) < headerSize || binary.LittleEndian.Uint16(data[0:]) != 0 || binary.LittleEndian.Uint16(data[2:]) != 1 {
+		return nil, ErrFormat
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	if count == 0 || len(data) < headerSize+entrySize*count {
+		return nil, ErrFormat
+	}
+
+	best, bestSize := -1, 0
+	for i := 0; i < count; i++ {
+		width := int(data[headerSize+entrySize*i])
+		if width == 0 {
+			width = 256
+		}
+
+		switch {
+		case best < 0,
+			width >= size && (bestSize < size || width < bestSize),
+			bestSize < size && width > bestSize:
+			best, bestSize = i, width
+		}
+	}
+
+	entry := data[headerSize+entrySize*best:]
+	length, offset := binary.LittleEndian.Uint32(entry[8:]), binary.LittleEndian.Uint32(entry[12:])
+	if uint64(offset)+uint64(length) > uint64(len(data)) {
+		return nil, ErrFormat
+	}
+
+	return data[offset : offset+length], nil
+}
+
+// Resize scales the image to fit a square of the given size, keeping the aspect ratio. Each pixel is the average of
+// the pixels covered by it, so the downscaled image doesn't alias.
+func Resize(img image.Image, size int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+
+	src := img.Bounds()
+	if src.Empty() {
+		return dst
+	}
+
+	// The content is centered in the square, the remaining area is transparent.
+	width, height := size, size
+	if src.Dx() > src.Dy() {
+		height = maxInt(1, (src.Dy()*size+src.Dx()/2)/src.Dx())
+	} else {
+		width = maxInt(1, (src.Dx()*size+src.Dy()/2)/src.Dy())
+	}
+	left, top := (size-width)/2, (size-height)/2
+
+	for y := 0; y < height; y++ {
+		y0, y1 := span(y, height, src.Dy())
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width, src.Dx())
+
+			// The colors are premultiplied, so the transparent pixels don't darken the edges.
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(src.Min.X+sx, src.Min.Y+sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+
+			c := color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+			dst.Set(left+x, top+y, color.NRGBAModel.Convert(c))
+		}
+	}
+
+	return dst
+}
+
+// span returns the range of source pixels covered by the destination pixel i, it covers at least one pixel.
+func span(i, dst, src int) (int, int) {
+	start, end := i*src/dst, (i+1)*src/dst
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,177 @@
+package ico
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// gradient creates a deterministic image, with a transparent corner.
+func gradient(width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			a := uint8(255)
+			if x < width/4 && y < height/4 {
+				a = 0
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: 128, A: a})
+		}
+	}
+	return img
+}
+
+func TestEncode_Golden(t *testing.T) {
+	tests := []struct {
+		name  string
+		img   image.Image
+		sizes []int
+	}{
+		{name: "square", img: gradient(64, 64), sizes: []int{16, 32, 48}},
+		{name: "wide", img: gradient(80, 40), sizes: []int{16, 32}},
+		{name: "upscale", img: gradient(8, 8), sizes: []int{16, 256}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			if err := Encode(b, tt.img, tt.sizes...); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", tt.name+".ico")
+			if *update {
+				if err := ioutil.WriteFile(golden, b.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The PNG encoder might change the compression, so the images are compared instead of the bytes.
+			if n := int(b.Bytes()[4]) | int(b.Bytes()[5])<<8; n != len(tt.sizes) {
+				t.Fatalf("expected %d images, got %d", len(tt.sizes), n)
+			}
+			for _, size := range tt.sizes {
+				got, want := decode(t, b.Bytes(), size), decode(t, expected, size)
+				if got.Bounds() != want.Bounds() {
+					t.Fatalf("expected %v for %d, got %v", want.Bounds(), size, got.Bounds())
+				}
+				if got.Bounds().Dx() != size || got.Bounds().Dy() != size {
+					t.Errorf("expected %dx%d, got %v", size, size, got.Bounds())
+				}
+
+				for y := got.Bounds().Min.Y; y < got.Bounds().Max.Y; y++ {
+					for x := got.Bounds().Min.X; x < got.Bounds().Max.X; x++ {
+						g := color.NRGBAModel.Convert(got.At(x, y))
+						w := color.NRGBAModel.Convert(want.At(x, y))
+						if g != w {
+							t.Fatalf("the pixel (%d, %d) of %d differs from %s: expected %v, got %v, run with -update if the change is expected", x, y, size, golden, w, g)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// decode returns the image of the given size, which must be present in the ICO.
+func decode(t *testing.T, data []byte, size int) image.Image {
+	t.Helper()
+
+	b, err := Lookup(data, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestEncode_Size(t *testing.T) {
+	for _, size := range []int{0, -1, 257} {
+		if err := Encode(new(bytes.Buffer), gradient(4, 4), size); err != ErrSize {
+			t.Errorf("expected ErrSize for %d, got %v", size, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	b := new(bytes.Buffer)
+	if err := Encode(b, gradient(64, 64), 16, 32, 256); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		size     int
+		expected int
+	}{
+		{size: 16, expected: 16},
+		{size: 20, expected: 32},
+		{size: 32, expected: 32},
+		{size: 33, expected: 256},
+		{size: 512, expected: 256},
+		{size: 1, expected: 16},
+	}
+
+	for _, tt := range tests {
+		data, err := Lookup(b.Bytes(), tt.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if img.Bounds().Dx() != tt.expected {
+			t.Errorf("expected %d for %d, got %d", tt.expected, tt.size, img.Bounds().Dx())
+		}
+	}
+}
+
+func TestLookup_Invalid(t *testing.T) {
+	for _, data := range [][]byte{nil, {0, 0, 1, 0}, {0, 0, 1, 0, 0, 0}, {0, 0, 1, 0, 1, 0}} {
+		if _, err := Lookup(data, 16); err != ErrFormat {
+			t.Errorf("expected ErrFormat for %v, got %v", data, err)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	img := Resize(gradient(80, 40), 16)
+
+	if img.Bounds().Dx() != 16 || img.Bounds().Dy() != 16 {
+		t.Fatalf("expected 16x16, got %v", img.Bounds())
+	}
+
+	// The wide image is centered, the top and bottom are transparent.
+	if a := img.NRGBAAt(8, 0).A; a != 0 {
+		t.Errorf("expected transparent padding, got alpha %d", a)
+	}
+	if a := img.NRGBAAt(8, 15).A; a != 0 {
+		t.Errorf("expected transparent padding, got alpha %d", a)
+	}
+	if a := img.NRGBAAt(15, 8).A; a != 255 {
+		t.Errorf("expected opaque content, got alpha %d", a)
+	}
+
+	// The transparent pixels don't change the color of the opaque pixels.
+	if c := img.NRGBAAt(15, 8); c.B != 128 {
+		t.Errorf("expected blue 128, got %d", c.B)
+	}
+}
@@ -0,0 +1,212 @@
+// Package menu builds the tree of the menus, shared by the backends. It validates the items, fills the defaults of
+// the roles and assigns the identifiers used by the native menus.
+package menu
+
+import (
+	"errors"
+	"github.com/inkeliz/gowebview/internal/accel"
+)
+
+var (
+	// ErrInvalid is returned when the item is inconsistent, such as a separator with a submenu.
+	ErrInvalid = errors.New("invalid menu")
+
+	// ErrDuplicate is returned when two items have the same accelerator.
+	ErrDuplicate = errors.New("duplicate accelerator")
+)
+
+// Role is the predefined action of the item, which is performed by the backend.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleUndo
+	RoleRedo
+	RoleCut
+	RoleCopy
+	RolePaste
+	RoleSelectAll
+	RoleReload
+	RoleZoomIn
+	RoleZoomOut
+	RoleZoomReset
+	RoleFullscreen
+	RoleDevTools
+	RoleClose
+)
+
+var roles = map[Role]struct {
+	label       string
+	accelerator accel.Chord
+}{
+	RoleUndo:       {"Undo", accel.Chord{Modifiers: accel.Ctrl, Key: "Z"}},
+	RoleRedo:       {"Redo", accel.Chord{Modifiers: accel.Ctrl, Key: "Y"}},
+	RoleCut:        {"Cut", accel.Chord{Modifiers: accel.Ctrl, Key: "X"}},
+	RoleCopy:       {"Copy", accel.Chord{Modifiers: accel.Ctrl, Key: "C"}},
+	RolePaste:      {"Paste", accel.Chord{Modifiers: accel.Ctrl, Key: "V"}},
+	RoleSelectAll:  {"Select All", accel.Chord{Modifiers: accel.Ctrl, Key: "A"}},
+	RoleReload:     {"Reload", accel.Chord{Key: "F5"}},
+	RoleZoomIn:     {"Zoom In", accel.Chord{Modifiers: accel.Ctrl, Key: "Plus"}},
+	RoleZoomOut:    {"Zoom Out", accel.Chord{Modifiers: accel.Ctrl, Key: "Minus"}},
+	RoleZoomReset:  {"Actual Size", accel.Chord{Modifiers: accel.Ctrl, Key: "0"}},
+	RoleFullscreen: {"Full Screen", accel.Chord{Key: "F11"}},
+	RoleDevTools:   {"Developer Tools", accel.Chord{Modifiers: accel.Ctrl | accel.Shift, Key: "I"}},
+	RoleClose:      {"Close", accel.Chord{Modifiers: accel.Ctrl, Key: "W"}},
+}
+
+// Native returns true if the action of the role is its own keyboard shortcut, such as Ctrl+C for RoleCopy, which is
+// handled by the page. The backend performs it by sending the keys, so the accelerator must not be intercepted.
+func (r Role) Native() bool {
+	return r >= RoleUndo && r <= RoleSelectAll
+}
+
+// Kind is the kind of the item.
+type Kind int
+
+const (
+	Normal Kind = iota
+	Checkbox
+	Separator
+	Submenu
+)
+
+// Item is one item of the menu.
+type Item struct {
+	// ID is assigned by New, it's unique inside the Tree and never zero.
+	ID int
+
+	Kind  Kind
+	Label string
+	Role  Role
+
+	// Shortcut is the accelerator, such as "Ctrl+S". It's parsed by New into the Accelerator.
+	Shortcut    string
+	Accelerator accel.Chord
+
+	Checked  bool
+	Disabled bool
+	Children []*Item
+
+	// Source is the item given by the user, it's not used by the package.
+	Source interface{}
+}
+
+// HasAccelerator returns true if the item has an accelerator.
+func (i *Item) HasAccelerator() bool {
+	return i.Accelerator.Key != ""
+}
+
+// Tree is the validated menu.
+type Tree struct {
+	Items []*Item
+
+	items        map[int]*Item
+	accelerators map[accel.Chord]*Item
+}
+
+// New validates the items and assigns the IDs. The roles without Label or Shortcut use the default ones. The items
+// are modified, so they must not be shared between trees.
+func New(items []*Item) (*Tree, error) {
+	t := &Tree{Items: items, items: make(map[int]*Item), accelerators: make(map[accel.Chord]*Item)}
+	if err := t.add(items); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Tree) add(items []*Item) error {
+	for _, item := range items {
+		if item == nil {
+			return ErrInvalid
+		}
+
+		if item.Role != RoleNone {
+			role, ok := roles[item.Role]
+			if !ok || item.Kind != Normal {
+				return ErrInvalid
+			}
+			if item.Label == "" {
+				item.Label = role.label
+			}
+			if item.Shortcut == "" {
+				item.Accelerator = role.accelerator
+			}
+		}
+
+		if item.Shortcut != "" {
+			c, err := accel.Parse(item.Shortcut)
+			if err != nil {
+				return err
+			}
+			item.Accelerator = c
+		}
+
+		switch item.Kind {
+		case Separator:
+			if item.Label != "" || item.HasAccelerator() || len(item.Children) > 0 {
+				return ErrInvalid
+			}
+		case Submenu:
+			if item.HasAccelerator() {
+				return ErrInvalid
+			}
+		default:
+			if len(item.Children) > 0 {
+				return ErrInvalid
+			}
+		}
+
+		item.ID = len(t.items) + 1
+		t.items[item.ID] = item
+
+		// The native roles are handled by the page, so their accelerators are not intercepted.
+		if item.HasAccelerator() && !item.Role.Native() {
+			if _, ok := t.accelerators[item.Accelerator]; ok {
+				return ErrDuplicate
+			}
+			t.accelerators[item.Accelerator] = item
+		}
+
+		if err := t.add(item.Children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Find returns the item with the given ID, or nil.
+func (t *Tree) Find(id int) *Item {
+	if t == nil {
+		return nil
+	}
+	return t.items[id]
+}
+
+// Lookup returns the item with the given accelerator, or nil. The items with native roles are never returned.
+func (t *Tree) Lookup(c accel.Chord) *Item {
+	if t == nil {
+		return nil
+	}
+	return t.accelerators[c]
+}
+
+// WithEdit returns the items preceded by the edit items (undo, redo, cut, copy, paste and select all), which are
+// the items of the default context menu of the page.
+func WithEdit(items []*Item) []*Item {
+	edit := []*Item{
+		{Role: RoleUndo},
+		{Role: RoleRedo},
+		{Kind: Separator},
+		{Role: RoleCut},
+		{Role: RoleCopy},
+		{Role: RolePaste},
+		{Kind: Separator},
+		{Role: RoleSelectAll},
+	}
+
+	if len(items) == 0 {
+		return edit
+	}
+	return append(append(edit, &Item{Kind: Separator}), items...)
+}
@@ -0,0 +1,102 @@
+package menu
+
+import (
+	"github.com/inkeliz/gowebview/internal/accel"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	save := &Item{Label: "Save", Shortcut: "Ctrl+S"}
+	recent := &Item{Label: "Recent", Kind: Checkbox, Checked: true}
+	file := &Item{Label: "File", Kind: Submenu, Children: []*Item{save, {Kind: Separator}, recent}}
+	copy := &Item{Role: RoleCopy}
+	reload := &Item{Role: RoleReload, Label: "Refresh", Shortcut: "Ctrl+R"}
+
+	tree, err := New([]*Item{file, copy, reload})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, expected := range map[int]*Item{1: file, 2: save, 4: recent, 5: copy, 6: reload} {
+		if item := tree.Find(id); item != expected {
+			t.Errorf("expected %v for %d, got %v", expected, id, item)
+		}
+	}
+
+	if tree.Find(0) != nil || tree.Find(7) != nil {
+		t.Error("expected nil for unknown ID")
+	}
+
+	if copy.Label != "Copy" || copy.Accelerator != (accel.Chord{Modifiers: accel.Ctrl, Key: "C"}) {
+		t.Errorf("expected the default label and accelerator, got %q and %v", copy.Label, copy.Accelerator)
+	}
+
+	if reload.Label != "Refresh" || reload.Accelerator != (accel.Chord{Modifiers: accel.Ctrl, Key: "R"}) {
+		t.Errorf("expected the given label and accelerator, got %q and %v", reload.Label, reload.Accelerator)
+	}
+
+	if item := tree.Lookup(accel.Chord{Modifiers: accel.Ctrl, Key: "S"}); item != save {
+		t.Errorf("expected the save item, got %v", item)
+	}
+
+	if item := tree.Lookup(accel.Chord{Modifiers: accel.Ctrl, Key: "C"}); item != nil {
+		t.Errorf("expected nil for the native role, got %v", item)
+	}
+
+	if item := tree.Lookup(accel.Chord{Key: "F5"}); item != nil {
+		t.Errorf("expected nil for the replaced accelerator, got %v", item)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []*Item
+		expected error
+	}{
+		{name: "nil", items: []*Item{nil}, expected: ErrInvalid},
+		{name: "separator label", items: []*Item{{Kind: Separator, Label: "-"}}, expected: ErrInvalid},
+		{name: "separator children", items: []*Item{{Kind: Separator, Children: []*Item{{Label: "A"}}}}, expected: ErrInvalid},
+		{name: "normal children", items: []*Item{{Label: "A", Children: []*Item{{Label: "B"}}}}, expected: ErrInvalid},
+		{name: "submenu accelerator", items: []*Item{{Kind: Submenu, Label: "A", Shortcut: "Ctrl+A"}}, expected: ErrInvalid},
+		{name: "role checkbox", items: []*Item{{Kind: Checkbox, Role: RoleCopy}}, expected: ErrInvalid},
+		{name: "unknown role", items: []*Item{{Role: Role(100)}}, expected: ErrInvalid},
+		{name: "shortcut", items: []*Item{{Label: "A", Shortcut: "Ctrl+"}}, expected: accel.ErrInvalid},
+		{name: "duplicate", items: []*Item{{Label: "A", Shortcut: "Ctrl+A"}, {Kind: Submenu, Label: "B", Children: []*Item{{Label: "C", Shortcut: "ctrl+a"}}}}, expected: ErrDuplicate},
+		{name: "nested", items: []*Item{{Kind: Submenu, Label: "A", Children: []*Item{{Kind: Separator, Label: "B"}}}}, expected: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		if _, err := New(tt.items); err != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestNew_NativeDuplicate(t *testing.T) {
+	// The native roles aren't intercepted, so they don't conflict with the other items.
+	if _, err := New([]*Item{{Role: RoleCopy}, {Label: "Copy", Shortcut: "Ctrl+C"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWithEdit(t *testing.T) {
+	custom := &Item{Label: "Inspect"}
+
+	items := WithEdit([]*Item{custom})
+	if len(items) != 10 || items[len(items)-1] != custom || items[len(items)-2].Kind != Separator {
+		t.Fatalf("expected the edit items, a separator and the custom item, got %d items", len(items))
+	}
+
+	if items[0].Role != RoleUndo || items[7].Role != RoleSelectAll {
+		t.Errorf("expected the edit roles first, got %v and %v", items[0].Role, items[7].Role)
+	}
+
+	if items := WithEdit(nil); len(items) != 8 {
+		t.Errorf("expected only the edit items, got %d", len(items))
+	}
+
+	if _, err := New(items); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Package migrate moves the browser data from one directory to another. Some files in the root of the old directory
+// can be kept there, such as the library extracted into the same directory by the previous versions.
+package migrate
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Move moves the content of the directory from into the new directory to, except the files in the root of from
+// whose names are in keep. It does nothing if from doesn't exist or if to already exists. The from is removed unless
+// some file is kept.
+//
+// The directory is renamed when nothing is kept, otherwise, or when the rename fails (such as between different
+// volumes), it's copied and then removed. If the copy fails, the to is removed and the from is left untouched.
+func Move(from, to string, keep []string) error {
+	if _, err := os.Stat(from); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := os.Stat(to); !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		if _, err := os.Lstat(filepath.Join(from, name)); err == nil {
+			kept[name] = true
+		}
+	}
+
+	if len(kept) == 0 {
+		if err := os.Rename(from, to); err == nil {
+			return nil
+		}
+	}
+
+	if err := copyDir(from, to, kept); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+
+	if len(kept) == 0 {
+		return os.RemoveAll(from)
+	}
+
+	entries, err := ioutil.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if kept[entry.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(from, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyDir copies the directory recursively, the skip are the names of the files in the root which aren't copied.
+func copyDir(from, to string, skip map[string]bool) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+
+		if skip[rel] {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dst := filepath.Join(to, rel)
+		if info.IsDir() {
+			return os.MkdirAll(dst, info.Mode().Perm())
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		file, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(file, src); err != nil {
+			file.Close()
+			return err
+		}
+
+		return file.Close()
+	})
+}
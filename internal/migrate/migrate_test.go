@@ -0,0 +1,157 @@
+package migrate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const library = "WebView2Loader.dll"
+
+// write creates the files, with their name as the content, inside the root.
+func write(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, name := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// list returns the files inside the root, checking their content, or nil if the root doesn't exist.
+func list(t *testing.T, root string) []string {
+	t.Helper()
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	files := []string{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if b, err := ioutil.ReadFile(path); err != nil || string(b) != rel {
+			t.Errorf("unexpected content of %s: %q", rel, b)
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(files)
+	return files
+}
+
+func TestMove(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		from, to []string // nil doesn't create the directory.
+		wantFrom []string
+		wantTo   []string
+	}{
+		{
+			name: "missing from",
+		},
+		{
+			name:     "existing to",
+			from:     []string{"Default/Cookies"},
+			to:       []string{"Local State"},
+			wantFrom: []string{"Default/Cookies"},
+			wantTo:   []string{"Local State"},
+		},
+		{
+			name:   "rename",
+			from:   []string{"Default/Cookies", "Local State"},
+			wantTo: []string{"Default/Cookies", "Local State"},
+		},
+		{
+			name:     "keep library",
+			from:     []string{library, "Default/Cookies", "Local State"},
+			wantFrom: []string{library},
+			wantTo:   []string{"Default/Cookies", "Local State"},
+		},
+		{
+			name:     "only library",
+			from:     []string{library},
+			wantFrom: []string{library},
+			wantTo:   []string{},
+		},
+		{
+			name:   "library in subdirectory",
+			from:   []string{"Default/" + library},
+			wantTo: []string{"Default/" + library},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "migrate")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(root)
+
+			from, to := filepath.Join(root, "old"), filepath.Join(root, "new", "data")
+			if test.from != nil {
+				write(t, from, test.from...)
+			}
+			if test.to != nil {
+				write(t, to, test.to...)
+			}
+
+			if err := Move(from, to, []string{library}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := list(t, from); !reflect.DeepEqual(got, test.wantFrom) {
+				t.Errorf("from: expected %v, got %v", test.wantFrom, got)
+			}
+			if got := list(t, to); !reflect.DeepEqual(got, test.wantTo) {
+				t.Errorf("to: expected %v, got %v", test.wantTo, got)
+			}
+		})
+	}
+}
+
+func TestMove_CopyFails(t *testing.T) {
+	root, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	from, to := filepath.Join(root, "old"), filepath.Join(root, "new")
+	write(t, from, library, "Default/Cookies", "Local State")
+
+	// The unreadable file fails the copy, which is used since the library is kept.
+	locked := filepath.Join(from, "Local State")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(locked, 0644)
+	if f, err := os.Open(locked); err == nil {
+		f.Close()
+		t.Skip("the file is still readable, such as when running as root")
+	}
+
+	if err := Move(from, to, []string{library}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if _, err := os.Stat(to); !os.IsNotExist(err) {
+		t.Errorf("the new directory wasn't removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(from, "Default", "Cookies")); err != nil {
+		t.Errorf("the old directory was changed: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+//+build !windows
+
+package profile
+
+import (
+	"os"
+	"syscall"
+)
+
+// lock acquires an exclusive lock on the file, it fails if the file is already locked.
+func lock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
@@ -0,0 +1,11 @@
+package profile
+
+import (
+	"golang.org/x/sys/windows"
+	"os"
+)
+
+// lock acquires an exclusive lock on the file, it fails if the file is already locked.
+func lock(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, new(windows.Overlapped))
+}
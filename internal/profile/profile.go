@@ -0,0 +1,159 @@
+// Package profile manages the directories of the ephemeral profiles. Each ephemeral profile is a random directory,
+// which is locked while it's in use. If the process crashes, the directory is removed by Sweep on the next execution.
+package profile
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	prefix   = "ephemeral-"
+	lockName = "gowebview.lock"
+
+	// minAge is the age of the directories removed by Sweep. The directory is created before it's locked, so the
+	// newer directories might belong to a process which is still inside NewEphemeral.
+	minAge = time.Minute
+)
+
+// Ephemeral is a temporary profile directory, which must be removed when it's no longer used.
+type Ephemeral struct {
+	Path string
+	lock *os.File
+}
+
+// NewEphemeral creates a new random directory inside the given root and locks it, until Remove is called.
+func NewEphemeral(root string) (*Ephemeral, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(root, prefix+hex.EncodeToString(b))
+	if err := os.Mkdir(path, 0700); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(path, lockName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		os.RemoveAll(path)
+		return nil, err
+	}
+
+	if err := lock(file); err != nil {
+		file.Close()
+		os.RemoveAll(path)
+		return nil, err
+	}
+
+	return &Ephemeral{Path: path, lock: file}, nil
+}
+
+// Remove overwrites the content of all files and deletes the directory. It can be called again if it fails, such as
+// when some file is still in use.
+func (e *Ephemeral) Remove() error {
+	if err := wipe(e.Path); err != nil {
+		return err
+	}
+
+	if e.lock != nil {
+		e.lock.Close()
+		e.lock = nil
+	}
+
+	return os.RemoveAll(e.Path)
+}
+
+// Sweep removes all ephemeral directories inside the given root that aren't locked, which are left by processes that
+// didn't exit properly. The directories created in the last minute are kept, since they might not be locked yet. It
+// returns the last error, if some directory can't be removed.
+func Sweep(root string) (err error) {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) || time.Since(entry.ModTime()) < minAge {
+			continue
+		}
+
+		path := filepath.Join(root, entry.Name())
+
+		file, lerr := os.OpenFile(filepath.Join(path, lockName), os.O_RDWR, 0600)
+		if lerr == nil {
+			lerr = lock(file)
+			if lerr != nil {
+				// It's locked by another process, which is still running.
+				file.Close()
+				continue
+			}
+		} else if !os.IsNotExist(lerr) {
+			err = lerr
+			continue
+		}
+
+		e := &Ephemeral{Path: path, lock: file}
+		if rerr := e.Remove(); rerr != nil {
+			if e.lock != nil {
+				e.lock.Close()
+			}
+			err = rerr
+		}
+	}
+
+	return err
+}
+
+// wipe overwrites all files with zeros, except the lock file.
+func wipe(path string) error {
+	return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() || info.Name() == lockName {
+			return nil
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.CopyN(file, zero{}, info.Size()); err != nil {
+			file.Close()
+			return err
+		}
+
+		if err = file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+
+		return file.Close()
+	})
+}
+
+// zero implements io.Reader, which reads infinite zeros.
+type zero struct{}
+
+func (zero) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
@@ -0,0 +1,101 @@
+package profile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEphemeral_Remove(t *testing.T) {
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	e, err := NewEphemeral(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(e.Path, "Default", "Cache"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(e.Path, "Default", "Cache", "data"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Remove(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(e.Path); !os.IsNotExist(err) {
+		t.Error("the directory still exists after Remove")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	running, err := NewEphemeral(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer running.Remove()
+
+	crashed, err := NewEphemeral(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulates a crash: the lock is released, but the directory is never removed.
+	crashed.lock.Close()
+	old := time.Now().Add(-2 * minAge)
+	if err := os.Chtimes(crashed.Path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulates a process which created the directory, but didn't lock it yet.
+	creating := filepath.Join(root, prefix+"creating")
+	if err := os.Mkdir(creating, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	unrelated := filepath.Join(root, "unrelated")
+	if err := os.Mkdir(unrelated, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Sweep(root); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(crashed.Path); !os.IsNotExist(err) {
+		t.Error("the directory of the crashed process wasn't removed")
+	}
+
+	if _, err := os.Stat(running.Path); err != nil {
+		t.Error("the directory in use was removed", err)
+	}
+
+	if _, err := os.Stat(creating); err != nil {
+		t.Error("the directory which is being created was removed", err)
+	}
+
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Error("the directory which isn't ephemeral was removed", err)
+	}
+}
+
+func TestSweep_NotExist(t *testing.T) {
+	if err := Sweep(filepath.Join(os.TempDir(), "gowebview-not-exist")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,94 @@
+// Package recovery implements the retry and backoff policy used to recover the browser after its process fails. It
+// doesn't depend on the browser, the caller reports each failure and schedules the retry using the returned delay.
+package recovery
+
+import (
+	"time"
+)
+
+// The default values, used when the Policy field is zero.
+const (
+	DefaultMaxRetries   = 3
+	DefaultInitialDelay = time.Second
+	DefaultMaxDelay     = 30 * time.Second
+	DefaultResetAfter   = time.Minute
+)
+
+// Policy defines how many times, and how often, the recovery is attempted. The zero values are replaced by the
+// defaults.
+type Policy struct {
+	// MaxRetries is the maximum number of consecutive attempts.
+	MaxRetries int
+
+	// InitialDelay is the delay before the first attempt, it doubles on each consecutive attempt.
+	InitialDelay time.Duration
+
+	// MaxDelay is the maximum delay between attempts.
+	MaxDelay time.Duration
+
+	// ResetAfter is the period without failures which resets the number of attempts.
+	ResetAfter time.Duration
+}
+
+// Backoff keeps the state of the consecutive failures. It isn't safe for concurrent use.
+type Backoff struct {
+	policy   Policy
+	attempts int
+	last     time.Time
+}
+
+// New creates the Backoff using the given Policy.
+func New(policy Policy) *Backoff {
+	if policy.MaxRetries <= 0 {
+		policy.MaxRetries = DefaultMaxRetries
+	}
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = DefaultInitialDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultMaxDelay
+	}
+	if policy.MaxDelay < policy.InitialDelay {
+		policy.MaxDelay = policy.InitialDelay
+	}
+	if policy.ResetAfter <= 0 {
+		policy.ResetAfter = DefaultResetAfter
+	}
+
+	return &Backoff{policy: policy}
+}
+
+// Failed reports one failure, at the given time. It returns the delay before the next attempt, or false if the
+// retry budget is exhausted.
+func (b *Backoff) Failed(now time.Time) (delay time.Duration, ok bool) {
+	if !b.last.IsZero() && now.Sub(b.last) >= b.policy.ResetAfter {
+		b.attempts = 0
+	}
+	b.last = now
+
+	if b.attempts >= b.policy.MaxRetries {
+		return 0, false
+	}
+	b.attempts++
+
+	delay = b.policy.InitialDelay
+	for i := 1; i < b.attempts; i++ {
+		delay *= 2
+		if delay >= b.policy.MaxDelay {
+			return b.policy.MaxDelay, true
+		}
+	}
+
+	return delay, true
+}
+
+// Attempts returns the number of consecutive attempts.
+func (b *Backoff) Attempts() int {
+	return b.attempts
+}
+
+// Reset forgets the previous failures.
+func (b *Backoff) Reset() {
+	b.attempts = 0
+	b.last = time.Time{}
+}
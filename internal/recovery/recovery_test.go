@@ -0,0 +1,90 @@
+package recovery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_Failed(t *testing.T) {
+	b := New(Policy{MaxRetries: 5, InitialDelay: time.Second, MaxDelay: 5 * time.Second, ResetAfter: time.Hour})
+
+	now := time.Now()
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		now = now.Add(time.Second)
+
+		delay, ok := b.Failed(now)
+		if !ok {
+			t.Fatalf("attempt %d: unexpected exhausted budget", i)
+		}
+
+		if delay != e {
+			t.Errorf("attempt %d: expected %s, got %s", i, e, delay)
+		}
+	}
+
+	if _, ok := b.Failed(now.Add(time.Second)); ok {
+		t.Error("expected exhausted budget")
+	}
+
+	if b.Attempts() != 5 {
+		t.Errorf("expected 5 attempts, got %d", b.Attempts())
+	}
+}
+
+func TestBackoff_ResetAfter(t *testing.T) {
+	b := New(Policy{MaxRetries: 2, InitialDelay: time.Second, ResetAfter: time.Minute})
+
+	now := time.Now()
+	b.Failed(now)
+	b.Failed(now.Add(time.Second))
+
+	if _, ok := b.Failed(now.Add(2 * time.Second)); ok {
+		t.Fatal("expected exhausted budget")
+	}
+
+	// Without failures for the ResetAfter period, the budget is restored.
+	delay, ok := b.Failed(now.Add(2*time.Second + time.Minute))
+	if !ok {
+		t.Fatal("expected the budget to be restored")
+	}
+
+	if delay != time.Second {
+		t.Errorf("expected the initial delay, got %s", delay)
+	}
+}
+
+func TestBackoff_Reset(t *testing.T) {
+	b := New(Policy{MaxRetries: 1})
+
+	now := time.Now()
+	b.Failed(now)
+	b.Reset()
+
+	if delay, ok := b.Failed(now); !ok || delay != DefaultInitialDelay {
+		t.Errorf("unexpected result after Reset: %s %v", delay, ok)
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	b := New(Policy{})
+
+	now := time.Now()
+	for i := 0; i < DefaultMaxRetries; i++ {
+		if _, ok := b.Failed(now); !ok {
+			t.Fatalf("attempt %d: unexpected exhausted budget", i)
+		}
+	}
+
+	if _, ok := b.Failed(now); ok {
+		t.Error("expected exhausted budget")
+	}
+
+	b = New(Policy{InitialDelay: time.Minute})
+	if delay, _ := b.Failed(now); delay != time.Minute {
+		t.Errorf("expected %s, got %s", time.Minute, delay)
+	}
+	if delay, _ := b.Failed(now); delay != time.Minute {
+		t.Errorf("the MaxDelay must not be lower than the InitialDelay, got %s", delay)
+	}
+}
@@ -0,0 +1,109 @@
+// Package version parses and compares the versions of the browser runtime. The version has up to four numeric parts,
+// such as "86.0.616.0", and it might be followed by the release channel, such as "88.0.705.0 canary".
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalid is returned when the version can't be parsed.
+	ErrInvalid = errors.New("invalid version")
+
+	// ErrUnsupported is returned by Check when the installed version is older than the minimum.
+	ErrUnsupported = errors.New("browser runtime version not supported")
+)
+
+// Version is the version of the browser runtime.
+type Version struct {
+	Major, Minor, Build, Patch int
+
+	// Channel is the release channel, such as "beta", "dev" or "canary". It's empty for the stable channel.
+	Channel string
+}
+
+// Parse parses the version. The missing parts are zero, so "86" is the same of "86.0.0.0".
+func Parse(s string) (v Version, err error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		v.Channel = strings.ToLower(strings.TrimSpace(s[i+1:]))
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 4 {
+		return Version{}, ErrInvalid
+	}
+
+	fields := [...]*int{&v.Major, &v.Minor, &v.Build, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 31)
+		if err != nil {
+			return Version{}, ErrInvalid
+		}
+		*fields[i] = int(n)
+	}
+
+	return v, nil
+}
+
+// IsZero returns true if the version is empty, which is "0.0.0.0".
+func (v Version) IsZero() bool {
+	return v.Major == 0 && v.Minor == 0 && v.Build == 0 && v.Patch == 0
+}
+
+// Compare returns -1 if v is older than o, 1 if v is newer than o and 0 if both are the same. The Channel is ignored.
+func (v Version) Compare(o Version) int {
+	a := [...]int{v.Major, v.Minor, v.Build, v.Patch}
+	b := [...]int{o.Major, o.Minor, o.Build, o.Patch}
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast returns true if v is the same or newer than the minimum.
+func (v Version) AtLeast(minimum Version) bool {
+	return v.Compare(minimum) >= 0
+}
+
+func (v Version) String() string {
+	s := strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Build) + "." + strconv.Itoa(v.Patch)
+	if v.Channel != "" {
+		s += " " + v.Channel
+	}
+	return s
+}
+
+// Check returns ErrUnsupported if the installed version is older than the minimum. The empty minimum accepts any
+// version, the invalid minimum returns ErrInvalid. The unknown installed version is accepted, since it can't be
+// compared.
+func Check(installed, minimum string) error {
+	if minimum == "" {
+		return nil
+	}
+
+	m, err := Parse(minimum)
+	if err != nil {
+		return fmt.Errorf("%w: minimum %q", ErrInvalid, minimum)
+	}
+
+	v, err := Parse(installed)
+	if err != nil {
+		return nil
+	}
+
+	if !v.AtLeast(m) {
+		return fmt.Errorf("%w: %s is older than %s", ErrUnsupported, v, m)
+	}
+
+	return nil
+}
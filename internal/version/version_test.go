@@ -0,0 +1,105 @@
+package version
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Version
+	}{
+		{"86.0.616.0", Version{Major: 86, Minor: 0, Build: 616, Patch: 0}},
+		{"88.0.705.50", Version{Major: 88, Minor: 0, Build: 705, Patch: 50}},
+		{"88.0.705.0 canary", Version{Major: 88, Minor: 0, Build: 705, Channel: "canary"}},
+		{" 87.0.664.8 Beta ", Version{Major: 87, Minor: 0, Build: 664, Patch: 8, Channel: "beta"}},
+		{"86", Version{Major: 86}},
+		{"86.1", Version{Major: 86, Minor: 1}},
+	}
+
+	for _, test := range tests {
+		v, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.input, err)
+			continue
+		}
+
+		if v != test.expected {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, v)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"", " ", "86.0.616.0.1", "86..616", "86.0.x.0", "-86.0", "v86.0"} {
+		if _, err := Parse(input); err != ErrInvalid {
+			t.Errorf("%q: expected ErrInvalid, got %v", input, err)
+		}
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"86.0.616.0", "86.0.616.0", 0},
+		{"86.0.616.0", "86.0.616.1", -1},
+		{"86.0.616.1", "86.0.616.0", 1},
+		{"86.0.616.0", "87.0.0.0", -1},
+		{"86.0.1000.0", "86.0.999.0", 1},
+		{"86", "86.0.0.0", 0},
+		{"88.0.705.0 canary", "88.0.705.0", 0},
+	}
+
+	for _, test := range tests {
+		a, _ := Parse(test.a)
+		b, _ := Parse(test.b)
+
+		if r := a.Compare(b); r != test.expected {
+			t.Errorf("%q, %q: expected %d, got %d", test.a, test.b, test.expected, r)
+		}
+
+		if a.AtLeast(b) != (test.expected >= 0) {
+			t.Errorf("%q, %q: unexpected AtLeast", test.a, test.b)
+		}
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	for _, input := range []string{"86.0.616.0", "88.0.705.0 canary"} {
+		v, err := Parse(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v.String() != input {
+			t.Errorf("expected %q, got %q", input, v.String())
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		installed, minimum string
+		expected           error
+	}{
+		{"86.0.616.0", "", nil},
+		{"", "", nil},
+		{"86.0.616.0", "86.0.616.0", nil},
+		{"87.0.664.75", "86.0.616.0", nil},
+		{"88.0.705.0 canary", "88.0.705.0", nil},
+		{"86.0.616.0", "86.0.616.1", ErrUnsupported},
+		{"85.0.564.70", "86", ErrUnsupported},
+		{"", "86.0.616.0", nil},
+		{"unknown", "86.0.616.0", nil},
+		{"86.0.616.0", "latest", ErrInvalid},
+	}
+
+	for _, test := range tests {
+		if err := Check(test.installed, test.minimum); !errors.Is(err, test.expected) || (err == nil) != (test.expected == nil) {
+			t.Errorf("%q, %q: expected %v, got %v", test.installed, test.minimum, test.expected, err)
+		}
+	}
+}
@@ -196,4 +196,663 @@ type (
 
 	// ICoreWebView2CreateCoreWebView2ControllerCompletedHandlerInvoke: public HRESULT Invoke(HRESULT errorCode, ICoreWebView2Controller * createdController)
 	ICoreWebView2CreateCoreWebView2ControllerCompletedHandlerInvoke func(i *ICoreWebView2CreateCoreWebView2ControllerCompletedHandler, p uintptr, createdController *ICoreWebView2Controller) uintptr
-)
\ No newline at end of file
+)
+type (
+	// ICoreWebView2CallDevToolsProtocolMethodCompletedHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2calldevtoolsprotocolmethodcompletedhandler
+	ICoreWebView2CallDevToolsProtocolMethodCompletedHandler struct {
+		Basic
+		VTBL *ICoreWebView2CallDevToolsProtocolMethodCompletedHandlerVTBL
+	}
+
+	// ICoreWebView2CallDevToolsProtocolMethodCompletedHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2calldevtoolsprotocolmethodcompletedhandler
+	ICoreWebView2CallDevToolsProtocolMethodCompletedHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2CallDevToolsProtocolMethodCompletedHandlerInvoke: public HRESULT Invoke(HRESULT errorCode, LPCWSTR returnObjectAsJson)
+	ICoreWebView2CallDevToolsProtocolMethodCompletedHandlerInvoke func(i *ICoreWebView2CallDevToolsProtocolMethodCompletedHandler, errorCode uintptr, returnObjectAsJson *uint16) uintptr
+)
+
+// EventRegistrationToken is the token returned when adding an event handler, which is used to remove it.
+type EventRegistrationToken int64
+
+type (
+	// ICoreWebView2ProcessFailedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2processfailedeventhandler
+	ICoreWebView2ProcessFailedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2ProcessFailedEventHandlerVTBL
+	}
+
+	// ICoreWebView2ProcessFailedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2processfailedeventhandler
+	ICoreWebView2ProcessFailedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2ProcessFailedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2 * sender, ICoreWebView2ProcessFailedEventArgs * args)
+	ICoreWebView2ProcessFailedEventHandlerInvoke func(i *ICoreWebView2ProcessFailedEventHandler, sender *ICoreWebView2, args *ICoreWebView2ProcessFailedEventArgs) uintptr
+)
+
+type (
+	// ICoreWebView2ProcessFailedEventArgs implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2processfailedeventargs
+	ICoreWebView2ProcessFailedEventArgs struct {
+		VTBL *ICoreWebView2ProcessFailedEventArgsVTBL
+	}
+
+	// ICoreWebView2ProcessFailedEventArgsVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2processfailedeventargs
+	ICoreWebView2ProcessFailedEventArgsVTBL struct {
+		BasicVTBL
+		GetProcessFailedKind uintptr
+	}
+)
+
+// IID_ICoreWebView2ProcessFailedEventArgs2 is the IID of ICoreWebView2ProcessFailedEventArgs2, which isn't available on
+// older runtimes.
+var IID_ICoreWebView2ProcessFailedEventArgs2 = windows.GUID{Data1: 0x4dab9422, Data2: 0x46fa, Data3: 0x4c3e, Data4: [8]byte{0xa5, 0xd2, 0x41, 0xd2, 0x07, 0x1d, 0x36, 0x80}}
+
+type (
+	// ICoreWebView2ProcessFailedEventArgs2 implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2processfailedeventargs2
+	ICoreWebView2ProcessFailedEventArgs2 struct {
+		VTBL *ICoreWebView2ProcessFailedEventArgs2VTBL
+	}
+
+	// ICoreWebView2ProcessFailedEventArgs2VTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2processfailedeventargs2
+	ICoreWebView2ProcessFailedEventArgs2VTBL struct {
+		BasicVTBL
+		GetProcessFailedKind          uintptr
+		GetReason                     uintptr
+		GetExitCode                   uintptr
+		GetProcessDescription         uintptr
+		GetFrameInfosForFailedProcess uintptr
+	}
+)
+
+// COREWEBVIEW2_PROCESS_FAILED_KIND implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/webview2-idl#corewebview2_process_failed_kind
+const (
+	COREWEBVIEW2_PROCESS_FAILED_KIND_BROWSER_PROCESS_EXITED = iota
+	COREWEBVIEW2_PROCESS_FAILED_KIND_RENDER_PROCESS_EXITED
+	COREWEBVIEW2_PROCESS_FAILED_KIND_RENDER_PROCESS_UNRESPONSIVE
+)
+
+type (
+	// ICoreWebView2WindowCloseRequestedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2windowcloserequestedeventhandler
+	ICoreWebView2WindowCloseRequestedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2WindowCloseRequestedEventHandlerVTBL
+	}
+
+	// ICoreWebView2WindowCloseRequestedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2windowcloserequestedeventhandler
+	ICoreWebView2WindowCloseRequestedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2WindowCloseRequestedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2 * sender, IUnknown * args)
+	ICoreWebView2WindowCloseRequestedEventHandlerInvoke func(i *ICoreWebView2WindowCloseRequestedEventHandler, sender *ICoreWebView2, args uintptr) uintptr
+)
+
+// IID_ICoreWebView2Settings3 is the IID of ICoreWebView2Settings3, which isn't available on older runtimes.
+var IID_ICoreWebView2Settings3 = windows.GUID{Data1: 0xfdb5ab74, Data2: 0xaf33, Data3: 0x4854, Data4: [8]byte{0x84, 0xf0, 0x0a, 0x63, 0x1d, 0xeb, 0x5e, 0xba}}
+
+// IID_ICoreWebView2Settings5 is the IID of ICoreWebView2Settings5, which isn't available on older runtimes.
+var IID_ICoreWebView2Settings5 = windows.GUID{Data1: 0x183e7052, Data2: 0x1d03, Data3: 0x43a0, Data4: [8]byte{0xab, 0x99, 0x98, 0xe0, 0x43, 0xb6, 0x6b, 0x39}}
+
+type (
+	// ICoreWebView2Settings implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2settings
+	// The functions of ICoreWebView2Settings2 to ICoreWebView2Settings5 can only be used on the interface returned by
+	// QueryInterface, using the respective IID.
+	ICoreWebView2Settings struct {
+		VTBL *ICoreWebView2SettingsVTBL
+	}
+
+	// ICoreWebView2SettingsVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2settings
+	ICoreWebView2SettingsVTBL struct {
+		BasicVTBL
+		GetIsScriptEnabled                  uintptr
+		PutIsScriptEnabled                  uintptr
+		GetIsWebMessageEnabled              uintptr
+		PutIsWebMessageEnabled              uintptr
+		GetAreDefaultScriptDialogsEnabled   uintptr
+		PutAreDefaultScriptDialogsEnabled   uintptr
+		GetIsStatusBarEnabled               uintptr
+		PutIsStatusBarEnabled               uintptr
+		GetAreDevToolsEnabled               uintptr
+		PutAreDevToolsEnabled               uintptr
+		GetAreDefaultContextMenusEnabled    uintptr
+		PutAreDefaultContextMenusEnabled    uintptr
+		GetAreHostObjectsAllowed            uintptr
+		PutAreHostObjectsAllowed            uintptr
+		GetIsZoomControlEnabled             uintptr
+		PutIsZoomControlEnabled             uintptr
+		GetIsBuiltInErrorPageEnabled        uintptr
+		PutIsBuiltInErrorPageEnabled        uintptr
+		GetUserAgent                        uintptr
+		PutUserAgent                        uintptr
+		GetAreBrowserAcceleratorKeysEnabled uintptr
+		PutAreBrowserAcceleratorKeysEnabled uintptr
+		GetIsPasswordAutosaveEnabled        uintptr
+		PutIsPasswordAutosaveEnabled        uintptr
+		GetIsGeneralAutofillEnabled         uintptr
+		PutIsGeneralAutofillEnabled         uintptr
+		GetIsPinchZoomEnabled               uintptr
+		PutIsPinchZoomEnabled               uintptr
+	}
+)
+
+type (
+	// ICoreWebView2ZoomFactorChangedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2zoomfactorchangedeventhandler
+	ICoreWebView2ZoomFactorChangedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2ZoomFactorChangedEventHandlerVTBL
+	}
+
+	// ICoreWebView2ZoomFactorChangedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2zoomfactorchangedeventhandler
+	ICoreWebView2ZoomFactorChangedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2ZoomFactorChangedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2Controller * sender, IUnknown * args)
+	ICoreWebView2ZoomFactorChangedEventHandlerInvoke func(i *ICoreWebView2ZoomFactorChangedEventHandler, sender *ICoreWebView2Controller, args uintptr) uintptr
+)
+
+// IID_ICoreWebView2Controller2 is the IID of ICoreWebView2Controller2, which isn't available on older runtimes.
+var IID_ICoreWebView2Controller2 = windows.GUID{Data1: 0xc979903e, Data2: 0xd4ca, Data3: 0x4228, Data4: [8]byte{0x92, 0xeb, 0x47, 0xee, 0x3f, 0xa9, 0x6e, 0xab}}
+
+type (
+	// ICoreWebView2Controller2 implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2controller2
+	ICoreWebView2Controller2 struct {
+		VTBL *ICoreWebView2Controller2VTBL
+	}
+
+	// ICoreWebView2Controller2VTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2controller2
+	ICoreWebView2Controller2VTBL struct {
+		ICoreWebView2ControllerVTBL
+		GetDefaultBackgroundColor uintptr
+		PutDefaultBackgroundColor uintptr
+	}
+)
+
+type (
+	// ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2addscripttoexecuteondocumentcreatedcompletedhandler
+	ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler struct {
+		Basic
+		VTBL *ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandlerVTBL
+	}
+
+	// ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2addscripttoexecuteondocumentcreatedcompletedhandler
+	ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandlerInvoke: public HRESULT Invoke(HRESULT errorCode, LPCWSTR id)
+	ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandlerInvoke func(i *ICoreWebView2AddScriptToExecuteOnDocumentCreatedCompletedHandler, errorCode uintptr, id *uint16) uintptr
+)
+
+type (
+	// ICoreWebView2ExecuteScriptCompletedHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2executescriptcompletedhandler
+	ICoreWebView2ExecuteScriptCompletedHandler struct {
+		Basic
+		VTBL *ICoreWebView2ExecuteScriptCompletedHandlerVTBL
+	}
+
+	// ICoreWebView2ExecuteScriptCompletedHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2executescriptcompletedhandler
+	ICoreWebView2ExecuteScriptCompletedHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2ExecuteScriptCompletedHandlerInvoke: public HRESULT Invoke(HRESULT errorCode, LPCWSTR resultObjectAsJson)
+	ICoreWebView2ExecuteScriptCompletedHandlerInvoke func(i *ICoreWebView2ExecuteScriptCompletedHandler, errorCode uintptr, resultObjectAsJson *uint16) uintptr
+)
+
+type (
+	// ICoreWebView2WebMessageReceivedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2webmessagereceivedeventhandler
+	ICoreWebView2WebMessageReceivedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2WebMessageReceivedEventHandlerVTBL
+	}
+
+	// ICoreWebView2WebMessageReceivedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2webmessagereceivedeventhandler
+	ICoreWebView2WebMessageReceivedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2WebMessageReceivedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2 * sender, ICoreWebView2WebMessageReceivedEventArgs * args)
+	ICoreWebView2WebMessageReceivedEventHandlerInvoke func(i *ICoreWebView2WebMessageReceivedEventHandler, sender *ICoreWebView2, args *ICoreWebView2WebMessageReceivedEventArgs) uintptr
+)
+
+type (
+	// ICoreWebView2WebMessageReceivedEventArgs implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2webmessagereceivedeventargs
+	ICoreWebView2WebMessageReceivedEventArgs struct {
+		VTBL *ICoreWebView2WebMessageReceivedEventArgsVTBL
+	}
+
+	// ICoreWebView2WebMessageReceivedEventArgsVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2webmessagereceivedeventargs
+	ICoreWebView2WebMessageReceivedEventArgsVTBL struct {
+		BasicVTBL
+		GetSource                uintptr
+		GetWebMessageAsJSON      uintptr
+		TryGetWebMessageAsString uintptr
+	}
+)
+
+type (
+	// ICoreWebView2ContainsFullScreenElementChangedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2containsfullscreenelementchangedeventhandler
+	ICoreWebView2ContainsFullScreenElementChangedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2ContainsFullScreenElementChangedEventHandlerVTBL
+	}
+
+	// ICoreWebView2ContainsFullScreenElementChangedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2containsfullscreenelementchangedeventhandler
+	ICoreWebView2ContainsFullScreenElementChangedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2ContainsFullScreenElementChangedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2 * sender, IUnknown * args)
+	ICoreWebView2ContainsFullScreenElementChangedEventHandlerInvoke func(i *ICoreWebView2ContainsFullScreenElementChangedEventHandler, sender *ICoreWebView2, args uintptr) uintptr
+)
+
+// CLSID_TaskbarList is the CLSID of the TaskbarList, which implements ITaskbarList3.
+var CLSID_TaskbarList = windows.GUID{Data1: 0x56fdf344, Data2: 0xfd6d, Data3: 0x11d0, Data4: [8]byte{0x95, 0x8a, 0x00, 0x60, 0x97, 0xc9, 0xa0, 0x90}}
+
+// IID_ITaskbarList3 is the IID of ITaskbarList3.
+var IID_ITaskbarList3 = windows.GUID{Data1: 0xea1afb91, Data2: 0x9e28, Data3: 0x4b86, Data4: [8]byte{0x90, 0xe9, 0x9e, 0x9f, 0x8a, 0x5e, 0xef, 0xaf}}
+
+type (
+	// ITaskbarList3 implements https://docs.microsoft.com/en-us/windows/win32/api/shobjidl_core/nn-shobjidl_core-itaskbarlist3
+	// It isn't part of WebView2, but it's used to display the progress and the badge on the taskbar.
+	ITaskbarList3 struct {
+		VTBL *ITaskbarList3VTBL
+	}
+
+	// ITaskbarList3VTBL implements https://docs.microsoft.com/en-us/windows/win32/api/shobjidl_core/nn-shobjidl_core-itaskbarlist3
+	ITaskbarList3VTBL struct {
+		BasicVTBL
+		HrInit                uintptr
+		AddTab                uintptr
+		DeleteTab             uintptr
+		ActivateTab           uintptr
+		SetActiveAlt          uintptr
+		MarkFullscreenWindow  uintptr
+		SetProgressValue      uintptr
+		SetProgressState      uintptr
+		RegisterTab           uintptr
+		UnregisterTab         uintptr
+		SetTabOrder           uintptr
+		SetTabActive          uintptr
+		ThumbBarAddButtons    uintptr
+		ThumbBarUpdateButtons uintptr
+		ThumbBarSetImageList  uintptr
+		SetOverlayIcon        uintptr
+		SetThumbnailTooltip   uintptr
+		SetThumbnailClip      uintptr
+	}
+)
+
+// TBPFLAG implements https://docs.microsoft.com/en-us/windows/win32/api/shobjidl_core/nf-shobjidl_core-itaskbarlist3-setprogressstate
+const (
+	TBPF_NOPROGRESS    = 0x0
+	TBPF_INDETERMINATE = 0x1
+	TBPF_NORMAL        = 0x2
+	TBPF_ERROR         = 0x4
+	TBPF_PAUSED        = 0x8
+)
+
+type (
+	// ICoreWebView2MoveFocusRequestedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2movefocusrequestedeventhandler
+	ICoreWebView2MoveFocusRequestedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2MoveFocusRequestedEventHandlerVTBL
+	}
+
+	// ICoreWebView2MoveFocusRequestedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2movefocusrequestedeventhandler
+	ICoreWebView2MoveFocusRequestedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2MoveFocusRequestedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2Controller * sender, ICoreWebView2MoveFocusRequestedEventArgs * args)
+	ICoreWebView2MoveFocusRequestedEventHandlerInvoke func(i *ICoreWebView2MoveFocusRequestedEventHandler, sender *ICoreWebView2Controller, args *ICoreWebView2MoveFocusRequestedEventArgs) uintptr
+)
+
+type (
+	// ICoreWebView2MoveFocusRequestedEventArgs implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2movefocusrequestedeventargs
+	ICoreWebView2MoveFocusRequestedEventArgs struct {
+		VTBL *ICoreWebView2MoveFocusRequestedEventArgsVTBL
+	}
+
+	// ICoreWebView2MoveFocusRequestedEventArgsVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2movefocusrequestedeventargs
+	ICoreWebView2MoveFocusRequestedEventArgsVTBL struct {
+		BasicVTBL
+		GetReason  uintptr
+		GetHandled uintptr
+		PutHandled uintptr
+	}
+)
+
+// COREWEBVIEW2_MOVE_FOCUS_REASON implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/webview2-idl#corewebview2_move_focus_reason
+const (
+	COREWEBVIEW2_MOVE_FOCUS_REASON_PROGRAMMATIC = iota
+	COREWEBVIEW2_MOVE_FOCUS_REASON_NEXT
+	COREWEBVIEW2_MOVE_FOCUS_REASON_PREVIOUS
+)
+
+type (
+	// ICoreWebView2AcceleratorKeyPressedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2acceleratorkeypressedeventhandler
+	ICoreWebView2AcceleratorKeyPressedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2AcceleratorKeyPressedEventHandlerVTBL
+	}
+
+	// ICoreWebView2AcceleratorKeyPressedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2acceleratorkeypressedeventhandler
+	ICoreWebView2AcceleratorKeyPressedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2AcceleratorKeyPressedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2Controller * sender, ICoreWebView2AcceleratorKeyPressedEventArgs * args)
+	ICoreWebView2AcceleratorKeyPressedEventHandlerInvoke func(i *ICoreWebView2AcceleratorKeyPressedEventHandler, sender *ICoreWebView2Controller, args *ICoreWebView2AcceleratorKeyPressedEventArgs) uintptr
+)
+
+type (
+	// ICoreWebView2AcceleratorKeyPressedEventArgs implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2acceleratorkeypressedeventargs
+	ICoreWebView2AcceleratorKeyPressedEventArgs struct {
+		VTBL *ICoreWebView2AcceleratorKeyPressedEventArgsVTBL
+	}
+
+	// ICoreWebView2AcceleratorKeyPressedEventArgsVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2acceleratorkeypressedeventargs
+	ICoreWebView2AcceleratorKeyPressedEventArgsVTBL struct {
+		BasicVTBL
+		GetKeyEventKind      uintptr
+		GetVirtualKey        uintptr
+		GetKeyEventLParam    uintptr
+		GetPhysicalKeyStatus uintptr
+		GetHandled           uintptr
+		PutHandled           uintptr
+	}
+)
+
+// COREWEBVIEW2_KEY_EVENT_KIND implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/webview2-idl#corewebview2_key_event_kind
+const (
+	COREWEBVIEW2_KEY_EVENT_KIND_KEY_DOWN = iota
+	COREWEBVIEW2_KEY_EVENT_KIND_KEY_UP
+	COREWEBVIEW2_KEY_EVENT_KIND_SYSTEM_KEY_DOWN
+	COREWEBVIEW2_KEY_EVENT_KIND_SYSTEM_KEY_UP
+)
+
+// IID_ICoreWebView2_11 is the IID of ICoreWebView2_11, which isn't available on older runtimes.
+var IID_ICoreWebView2_11 = windows.GUID{Data1: 0x0be78e56, Data2: 0xc193, Data3: 0x4051, Data4: [8]byte{0xb9, 0x43, 0x23, 0xb4, 0x60, 0xc0, 0x8b, 0xdb}}
+
+type (
+	// ICoreWebView2_11 implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2_11
+	// The VTBL includes the functions of ICoreWebView2_2 to ICoreWebView2_10, in order.
+	ICoreWebView2_11 struct {
+		VTBL *ICoreWebView2_11VTBL
+	}
+
+	// ICoreWebView2_11VTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2_11
+	ICoreWebView2_11VTBL struct {
+		ICoreWebView2VTBL
+		AddWebResourceResponseReceived           uintptr
+		RemoveWebResourceResponseReceived        uintptr
+		NavigateWithWebResourceRequest           uintptr
+		AddDOMContentLoaded                      uintptr
+		RemoveDOMContentLoaded                   uintptr
+		GetCookieManager                         uintptr
+		GetEnvironment                           uintptr
+		TrySuspend                               uintptr
+		Resume                                   uintptr
+		GetIsSuspended                           uintptr
+		SetVirtualHostNameToFolderMapping        uintptr
+		ClearVirtualHostNameToFolderMapping      uintptr
+		AddFrameCreated                          uintptr
+		RemoveFrameCreated                       uintptr
+		AddDownloadStarting                      uintptr
+		RemoveDownloadStarting                   uintptr
+		AddClientCertificateRequested            uintptr
+		RemoveClientCertificateRequested         uintptr
+		OpenTaskManagerWindow                    uintptr
+		PrintToPdf                               uintptr
+		AddIsMutedChanged                        uintptr
+		RemoveIsMutedChanged                     uintptr
+		GetIsMuted                               uintptr
+		PutIsMuted                               uintptr
+		AddIsDocumentPlayingAudioChanged         uintptr
+		RemoveIsDocumentPlayingAudioChanged      uintptr
+		GetIsDocumentPlayingAudio                uintptr
+		AddIsDefaultDownloadDialogOpenChanged    uintptr
+		RemoveIsDefaultDownloadDialogOpenChanged uintptr
+		GetIsDefaultDownloadDialogOpen           uintptr
+		OpenDefaultDownloadDialog                uintptr
+		CloseDefaultDownloadDialog               uintptr
+		GetDefaultDownloadDialogCornerAlignment  uintptr
+		PutDefaultDownloadDialogCornerAlignment  uintptr
+		GetDefaultDownloadDialogMargin           uintptr
+		PutDefaultDownloadDialogMargin           uintptr
+		AddBasicAuthenticationRequested          uintptr
+		RemoveBasicAuthenticationRequested       uintptr
+		CallDevToolsProtocolMethodForSession     uintptr
+		AddContextMenuRequested                  uintptr
+		RemoveContextMenuRequested               uintptr
+	}
+)
+
+// IID_ICoreWebView2_13 is the IID of ICoreWebView2_13, which isn't available on older runtimes.
+var IID_ICoreWebView2_13 = windows.GUID{Data1: 0xf75f09a8, Data2: 0x667e, Data3: 0x4983, Data4: [8]byte{0x88, 0xd6, 0xc8, 0x77, 0x3f, 0x31, 0x5e, 0x84}}
+
+type (
+	// ICoreWebView2_13 implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2_13
+	// The VTBL includes the functions of ICoreWebView2_2 to ICoreWebView2_12, in order.
+	ICoreWebView2_13 struct {
+		VTBL *ICoreWebView2_13VTBL
+	}
+
+	// ICoreWebView2_13VTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2_13
+	ICoreWebView2_13VTBL struct {
+		ICoreWebView2_11VTBL
+		AddStatusBarTextChanged    uintptr
+		RemoveStatusBarTextChanged uintptr
+		GetStatusBarText           uintptr
+		GetProfile                 uintptr
+	}
+)
+
+// IID_ICoreWebView2Profile2 is the IID of ICoreWebView2Profile2, which isn't available on older runtimes.
+var IID_ICoreWebView2Profile2 = windows.GUID{Data1: 0xfa740d4b, Data2: 0x5eae, Data3: 0x4344, Data4: [8]byte{0xa8, 0xad, 0x74, 0xbe, 0x31, 0x92, 0x53, 0x97}}
+
+type (
+	// ICoreWebView2Profile2 implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2profile2
+	// The VTBL includes the functions of ICoreWebView2Profile.
+	ICoreWebView2Profile2 struct {
+		VTBL *ICoreWebView2Profile2VTBL
+	}
+
+	// ICoreWebView2Profile2VTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2profile2
+	ICoreWebView2Profile2VTBL struct {
+		BasicVTBL
+		GetProfileName               uintptr
+		GetIsInPrivateModeEnabled    uintptr
+		GetProfilePath               uintptr
+		GetDefaultDownloadFolderPath uintptr
+		PutDefaultDownloadFolderPath uintptr
+		GetPreferredColorScheme      uintptr
+		PutPreferredColorScheme      uintptr
+		ClearBrowsingData            uintptr
+		ClearBrowsingDataInTimeRange uintptr
+		ClearBrowsingDataAll         uintptr
+	}
+)
+
+type (
+	// ICoreWebView2ClearBrowsingDataCompletedHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2clearbrowsingdatacompletedhandler
+	ICoreWebView2ClearBrowsingDataCompletedHandler struct {
+		Basic
+		VTBL *ICoreWebView2ClearBrowsingDataCompletedHandlerVTBL
+	}
+
+	// ICoreWebView2ClearBrowsingDataCompletedHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2clearbrowsingdatacompletedhandler
+	ICoreWebView2ClearBrowsingDataCompletedHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2ClearBrowsingDataCompletedHandlerInvoke: public HRESULT Invoke(HRESULT errorCode)
+	ICoreWebView2ClearBrowsingDataCompletedHandlerInvoke func(i *ICoreWebView2ClearBrowsingDataCompletedHandler, errorCode uintptr) uintptr
+)
+
+// COREWEBVIEW2_BROWSING_DATA_KINDS implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/webview2-idl#corewebview2_browsing_data_kinds
+const (
+	COREWEBVIEW2_BROWSING_DATA_KINDS_FILE_SYSTEMS      = 1 << 0
+	COREWEBVIEW2_BROWSING_DATA_KINDS_INDEXED_DB        = 1 << 1
+	COREWEBVIEW2_BROWSING_DATA_KINDS_LOCAL_STORAGE     = 1 << 2
+	COREWEBVIEW2_BROWSING_DATA_KINDS_WEB_SQL           = 1 << 3
+	COREWEBVIEW2_BROWSING_DATA_KINDS_CACHE_STORAGE     = 1 << 4
+	COREWEBVIEW2_BROWSING_DATA_KINDS_ALL_DOM_STORAGE   = 1 << 5
+	COREWEBVIEW2_BROWSING_DATA_KINDS_COOKIES           = 1 << 6
+	COREWEBVIEW2_BROWSING_DATA_KINDS_ALL_SITE          = 1 << 7
+	COREWEBVIEW2_BROWSING_DATA_KINDS_DISK_CACHE        = 1 << 8
+	COREWEBVIEW2_BROWSING_DATA_KINDS_DOWNLOAD_HISTORY  = 1 << 9
+	COREWEBVIEW2_BROWSING_DATA_KINDS_GENERAL_AUTOFILL  = 1 << 10
+	COREWEBVIEW2_BROWSING_DATA_KINDS_PASSWORD_AUTOSAVE = 1 << 11
+	COREWEBVIEW2_BROWSING_DATA_KINDS_BROWSING_HISTORY  = 1 << 12
+	COREWEBVIEW2_BROWSING_DATA_KINDS_SETTINGS          = 1 << 13
+	COREWEBVIEW2_BROWSING_DATA_KINDS_ALL_PROFILE       = 1 << 14
+	COREWEBVIEW2_BROWSING_DATA_KINDS_SERVICE_WORKERS   = 1 << 15
+)
+
+// IID_ICoreWebView2Environment9 is the IID of ICoreWebView2Environment9, which isn't available on older runtimes.
+var IID_ICoreWebView2Environment9 = windows.GUID{Data1: 0xf06f41bf, Data2: 0x4b5a, Data3: 0x49d8, Data4: [8]byte{0xb9, 0xf6, 0xfa, 0x16, 0xcd, 0x29, 0xf2, 0x74}}
+
+type (
+	// ICoreWebView2Environment9 implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2environment9
+	// The VTBL includes the functions of ICoreWebView2Environment2 to ICoreWebView2Environment8, in order.
+	ICoreWebView2Environment9 struct {
+		VTBL *ICoreWebView2Environment9VTBL
+	}
+
+	// ICoreWebView2Environment9VTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2environment9
+	ICoreWebView2Environment9VTBL struct {
+		ICoreWebView2EnvironmentVTBL
+		CreateWebResourceRequest                uintptr
+		CreateCoreWebView2CompositionController uintptr
+		CreateCoreWebView2PointerInfo           uintptr
+		GetAutomationProviderForWindow          uintptr
+		AddBrowserProcessExited                 uintptr
+		RemoveBrowserProcessExited              uintptr
+		CreatePrintSettings                     uintptr
+		GetUserDataFolder                       uintptr
+		AddProcessInfosChanged                  uintptr
+		RemoveProcessInfosChanged               uintptr
+		GetProcessInfos                         uintptr
+		CreateContextMenuItem                   uintptr
+	}
+)
+
+type (
+	// ICoreWebView2ContextMenuRequestedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenurequestedeventhandler
+	ICoreWebView2ContextMenuRequestedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2ContextMenuRequestedEventHandlerVTBL
+	}
+
+	// ICoreWebView2ContextMenuRequestedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenurequestedeventhandler
+	ICoreWebView2ContextMenuRequestedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2ContextMenuRequestedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2 * sender, ICoreWebView2ContextMenuRequestedEventArgs * args)
+	ICoreWebView2ContextMenuRequestedEventHandlerInvoke func(i *ICoreWebView2ContextMenuRequestedEventHandler, sender *ICoreWebView2, args *ICoreWebView2ContextMenuRequestedEventArgs) uintptr
+)
+
+type (
+	// ICoreWebView2ContextMenuRequestedEventArgs implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenurequestedeventargs
+	ICoreWebView2ContextMenuRequestedEventArgs struct {
+		VTBL *ICoreWebView2ContextMenuRequestedEventArgsVTBL
+	}
+
+	// ICoreWebView2ContextMenuRequestedEventArgsVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenurequestedeventargs
+	ICoreWebView2ContextMenuRequestedEventArgsVTBL struct {
+		BasicVTBL
+		GetMenuItems         uintptr
+		GetContextMenuTarget uintptr
+		GetLocation          uintptr
+		PutSelectedCommandId uintptr
+		GetSelectedCommandId uintptr
+		PutHandled           uintptr
+		GetHandled           uintptr
+		GetDeferral          uintptr
+	}
+)
+
+type (
+	// ICoreWebView2ContextMenuItemCollection implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenuitemcollection
+	ICoreWebView2ContextMenuItemCollection struct {
+		VTBL *ICoreWebView2ContextMenuItemCollectionVTBL
+	}
+
+	// ICoreWebView2ContextMenuItemCollectionVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenuitemcollection
+	ICoreWebView2ContextMenuItemCollectionVTBL struct {
+		BasicVTBL
+		GetCount           uintptr
+		GetValueAtIndex    uintptr
+		RemoveValueAtIndex uintptr
+		InsertValueAtIndex uintptr
+	}
+)
+
+type (
+	// ICoreWebView2ContextMenuItem implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenuitem
+	ICoreWebView2ContextMenuItem struct {
+		VTBL *ICoreWebView2ContextMenuItemVTBL
+	}
+
+	// ICoreWebView2ContextMenuItemVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2contextmenuitem
+	ICoreWebView2ContextMenuItemVTBL struct {
+		BasicVTBL
+		GetName                   uintptr
+		GetLabel                  uintptr
+		GetCommandId              uintptr
+		GetShortcutKeyDescription uintptr
+		GetIcon                   uintptr
+		GetKind                   uintptr
+		PutIsEnabled              uintptr
+		GetIsEnabled              uintptr
+		PutIsChecked              uintptr
+		GetIsChecked              uintptr
+		GetChildren               uintptr
+		AddCustomItemSelected     uintptr
+		RemoveCustomItemSelected  uintptr
+	}
+)
+
+type (
+	// ICoreWebView2CustomItemSelectedEventHandler implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2customitemselectedeventhandler
+	ICoreWebView2CustomItemSelectedEventHandler struct {
+		Basic
+		VTBL *ICoreWebView2CustomItemSelectedEventHandlerVTBL
+	}
+
+	// ICoreWebView2CustomItemSelectedEventHandlerVTBL implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/icorewebview2customitemselectedeventhandler
+	ICoreWebView2CustomItemSelectedEventHandlerVTBL struct {
+		BasicVTBL
+		Invoke uintptr
+	}
+
+	// ICoreWebView2CustomItemSelectedEventHandlerInvoke: public HRESULT Invoke(ICoreWebView2ContextMenuItem * sender, IUnknown * args)
+	ICoreWebView2CustomItemSelectedEventHandlerInvoke func(i *ICoreWebView2CustomItemSelectedEventHandler, sender *ICoreWebView2ContextMenuItem, args uintptr) uintptr
+)
+
+// COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND implements https://docs.microsoft.com/en-us/microsoft-edge/webview2/reference/win32/webview2-idl#corewebview2_context_menu_item_kind
+const (
+	COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_COMMAND = iota
+	COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_CHECK_BOX
+	COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_RADIO
+	COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_SEPARATOR
+	COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_SUBMENU
+)
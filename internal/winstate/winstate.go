@@ -0,0 +1,121 @@
+// Package winstate encodes the state of the window and clamps the saved bounds to the attached screens, so the window
+// never reopens off-screen when a monitor is disconnected or the resolution changes.
+package winstate
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Version is the current version of the file format.
+const Version = 1
+
+// ErrVersion is returned when the state was saved by a newer, and unknown, version.
+var ErrVersion = errors.New("unsupported state version")
+
+// Rect is the position and size of the window or the screen.
+type Rect struct {
+	X, Y          int64
+	Width, Height int64
+}
+
+// State is the state of the window.
+type State struct {
+	// Bounds is the position and size of the window, when it's not maximized.
+	Bounds Rect
+
+	// Maximized is true if the window was maximized.
+	Maximized bool
+}
+
+type file struct {
+	Version   int   `json:"version"`
+	X         int64 `json:"x"`
+	Y         int64 `json:"y"`
+	Width     int64 `json:"width"`
+	Height    int64 `json:"height"`
+	Maximized bool  `json:"maximized,omitempty"`
+}
+
+// Encode encodes the State as JSON, using the current Version.
+func Encode(s State) ([]byte, error) {
+	return json.MarshalIndent(file{
+		Version:   Version,
+		X:         s.Bounds.X,
+		Y:         s.Bounds.Y,
+		Width:     s.Bounds.Width,
+		Height:    s.Bounds.Height,
+		Maximized: s.Maximized,
+	}, "", "\t")
+}
+
+// Decode decodes the State encoded by Encode. It returns ErrVersion if the version is newer than Version.
+func Decode(b []byte) (State, error) {
+	var f file
+	if err := json.Unmarshal(b, &f); err != nil {
+		return State{}, err
+	}
+
+	if f.Version < 1 || f.Version > Version {
+		return State{}, ErrVersion
+	}
+
+	return State{
+		Bounds:    Rect{X: f.X, Y: f.Y, Width: f.Width, Height: f.Height},
+		Maximized: f.Maximized,
+	}, nil
+}
+
+// Clamp moves and resizes the bounds to fit entirely inside one of the work areas. It uses the work area which
+// contains the largest part of the window, or the first one (the primary screen) if the window is outside all of
+// them, in that case the window is centered. The bounds are returned as is if there is no work area.
+func Clamp(bounds Rect, workAreas []Rect) Rect {
+	if len(workAreas) == 0 {
+		return bounds
+	}
+
+	best, area := -1, int64(0)
+	for i, w := range workAreas {
+		if a := intersection(bounds, w); a > area {
+			best, area = i, a
+		}
+	}
+
+	if best < 0 {
+		w := workAreas[0]
+		bounds.Width, bounds.Height = min(bounds.Width, w.Width), min(bounds.Height, w.Height)
+		bounds.X = w.X + (w.Width-bounds.Width)/2
+		bounds.Y = w.Y + (w.Height-bounds.Height)/2
+		return bounds
+	}
+
+	w := workAreas[best]
+	bounds.Width, bounds.Height = min(bounds.Width, w.Width), min(bounds.Height, w.Height)
+	bounds.X = max(w.X, min(bounds.X, w.X+w.Width-bounds.Width))
+	bounds.Y = max(w.Y, min(bounds.Y, w.Y+w.Height-bounds.Height))
+	return bounds
+}
+
+// intersection returns the area of the intersection of both Rect.
+func intersection(a, b Rect) int64 {
+	w := min(a.X+a.Width, b.X+b.Width) - max(a.X, b.X)
+	h := min(a.Y+a.Height, b.Y+b.Height) - max(a.Y, b.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
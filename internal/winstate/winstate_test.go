@@ -0,0 +1,98 @@
+package winstate
+
+import (
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	s := State{Bounds: Rect{X: -1920, Y: 10, Width: 800, Height: 600}, Maximized: true}
+
+	b, err := Encode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r != s {
+		t.Errorf("expected %v, got %v", s, r)
+	}
+}
+
+func TestDecode_Version(t *testing.T) {
+	if _, err := Decode([]byte(`{"version": 2, "width": 800, "height": 600}`)); err != ErrVersion {
+		t.Errorf("expected ErrVersion, got %v", err)
+	}
+
+	if _, err := Decode([]byte(`{"width": 800, "height": 600}`)); err != ErrVersion {
+		t.Errorf("expected ErrVersion without version, got %v", err)
+	}
+
+	if _, err := Decode([]byte(`{`)); err == nil {
+		t.Error("expected error of invalid JSON")
+	}
+}
+
+func TestClamp(t *testing.T) {
+	primary := Rect{X: 0, Y: 0, Width: 1920, Height: 1040}
+	secondary := Rect{X: 1920, Y: 0, Width: 1280, Height: 1024}
+
+	tests := []struct {
+		name     string
+		bounds   Rect
+		areas    []Rect
+		expected Rect
+	}{
+		{
+			name:     "inside",
+			bounds:   Rect{X: 100, Y: 100, Width: 800, Height: 600},
+			areas:    []Rect{primary, secondary},
+			expected: Rect{X: 100, Y: 100, Width: 800, Height: 600},
+		},
+		{
+			name:     "secondary",
+			bounds:   Rect{X: 2000, Y: 100, Width: 800, Height: 600},
+			areas:    []Rect{primary, secondary},
+			expected: Rect{X: 2000, Y: 100, Width: 800, Height: 600},
+		},
+		{
+			name:     "disconnected monitor",
+			bounds:   Rect{X: 2000, Y: 100, Width: 800, Height: 600},
+			areas:    []Rect{primary},
+			expected: Rect{X: 560, Y: 220, Width: 800, Height: 600},
+		},
+		{
+			name:     "partially outside",
+			bounds:   Rect{X: 1500, Y: -50, Width: 800, Height: 600},
+			areas:    []Rect{primary},
+			expected: Rect{X: 1120, Y: 0, Width: 800, Height: 600},
+		},
+		{
+			name:     "largest intersection",
+			bounds:   Rect{X: 1700, Y: 100, Width: 800, Height: 600},
+			areas:    []Rect{primary, secondary},
+			expected: Rect{X: 1920, Y: 100, Width: 800, Height: 600},
+		},
+		{
+			name:     "larger than the screen",
+			bounds:   Rect{X: 0, Y: 0, Width: 3000, Height: 2000},
+			areas:    []Rect{primary},
+			expected: Rect{X: 0, Y: 0, Width: 1920, Height: 1040},
+		},
+		{
+			name:     "without screens",
+			bounds:   Rect{X: 5000, Y: 5000, Width: 800, Height: 600},
+			areas:    nil,
+			expected: Rect{X: 5000, Y: 5000, Width: 800, Height: 600},
+		},
+	}
+
+	for _, test := range tests {
+		if r := Clamp(test.bounds, test.areas); r != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, r)
+		}
+	}
+}
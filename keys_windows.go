@@ -0,0 +1,136 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/accel"
+	"github.com/inkeliz/w32"
+	"strconv"
+)
+
+// virtualKeys is kinda of `map[accel.Key]virtual-key`, the letters, digits and function keys are computed.
+var virtualKeys = map[accel.Key]uint16{
+	"Enter":        w32.VK_RETURN,
+	"Escape":       w32.VK_ESCAPE,
+	"Tab":          w32.VK_TAB,
+	"Space":        w32.VK_SPACE,
+	"Backspace":    w32.VK_BACK,
+	"Delete":       w32.VK_DELETE,
+	"Insert":       w32.VK_INSERT,
+	"Home":         w32.VK_HOME,
+	"End":          w32.VK_END,
+	"PageUp":       w32.VK_PRIOR,
+	"PageDown":     w32.VK_NEXT,
+	"Up":           w32.VK_UP,
+	"Down":         w32.VK_DOWN,
+	"Left":         w32.VK_LEFT,
+	"Right":        w32.VK_RIGHT,
+	"Plus":         w32.VK_OEM_PLUS,
+	"Minus":        w32.VK_OEM_MINUS,
+	"Equal":        w32.VK_OEM_PLUS,
+	"Comma":        w32.VK_OEM_COMMA,
+	"Period":       w32.VK_OEM_PERIOD,
+	"Slash":        w32.VK_OEM_2,
+	"Backslash":    w32.VK_OEM_5,
+	"Semicolon":    w32.VK_OEM_1,
+	"Quote":        w32.VK_OEM_7,
+	"BracketLeft":  w32.VK_OEM_4,
+	"BracketRight": w32.VK_OEM_6,
+	"Backquote":    w32.VK_OEM_3,
+}
+
+// virtualKey returns the virtual-key of the key, or zero if it's unknown.
+func virtualKey(key accel.Key) uint16 {
+	if vk, ok := virtualKeys[key]; ok {
+		return vk
+	}
+
+	switch {
+	case len(key) == 1 && (key[0] >= 'A' && key[0] <= 'Z' || key[0] >= '0' && key[0] <= '9'):
+		return uint16(key[0])
+	case len(key) > 1 && key[0] == 'F':
+		if n, err := strconv.Atoi(string(key[1:])); err == nil && n >= 1 && n <= 24 {
+			return w32.VK_F1 + uint16(n) - 1
+		}
+	}
+	return 0
+}
+
+// keyOf returns the key of the virtual-key, or an empty Key if it's unknown. The "Equal" is the same key of "Plus" on
+// most keyboards, so "Plus" is returned. The keys of the numeric keypad are the same of the main keyboard.
+func keyOf(vk uint16) accel.Key {
+	switch {
+	case vk >= 'A' && vk <= 'Z', vk >= '0' && vk <= '9':
+		return accel.Key(rune(vk))
+	case vk >= w32.VK_NUMPAD0 && vk <= w32.VK_NUMPAD9:
+		return accel.Key(rune('0' + vk - w32.VK_NUMPAD0))
+	case vk >= w32.VK_F1 && vk <= w32.VK_F24:
+		return accel.Key("F" + strconv.Itoa(int(vk-w32.VK_F1+1)))
+	case vk == w32.VK_ADD:
+		return "Plus"
+	case vk == w32.VK_SUBTRACT:
+		return "Minus"
+	case vk == w32.VK_OEM_PLUS:
+		return "Plus"
+	}
+
+	for key, v := range virtualKeys {
+		if v == vk {
+			return key
+		}
+	}
+	return ""
+}
+
+// pressedModifiers returns the modifiers which are pressed, using the state of the keyboard of the current message.
+func pressedModifiers() (m accel.Modifiers) {
+	for _, k := range []struct {
+		vk       int
+		modifier accel.Modifiers
+	}{
+		{w32.VK_CONTROL, accel.Ctrl},
+		{w32.VK_MENU, accel.Alt},
+		{w32.VK_SHIFT, accel.Shift},
+		{w32.VK_LWIN, accel.Super},
+		{w32.VK_RWIN, accel.Super},
+	} {
+		if w32.GetKeyState(k.vk)&0x8000 != 0 {
+			m |= k.modifier
+		}
+	}
+	return m
+}
+
+// sendChord simulates the key presses of the chord, such as Ctrl+C, which are received by the focused window.
+func sendChord(c accel.Chord) {
+	vk := virtualKey(c.Key)
+	if vk == 0 {
+		return
+	}
+
+	var modifiers []uint16
+	for _, k := range []struct {
+		vk       uint16
+		modifier accel.Modifiers
+	}{
+		{w32.VK_CONTROL, accel.Ctrl},
+		{w32.VK_MENU, accel.Alt},
+		{w32.VK_SHIFT, accel.Shift},
+		{w32.VK_LWIN, accel.Super},
+	} {
+		if c.Modifiers&k.modifier != 0 {
+			modifiers = append(modifiers, k.vk)
+		}
+	}
+
+	inputs := make([]w32.INPUT, 0, 2*len(modifiers)+2)
+	for _, m := range modifiers {
+		inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{Vk: m}))
+	}
+	inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{Vk: vk}), w32.KeyboardInput(w32.KEYBDINPUT{Vk: vk, Flags: w32.KEYEVENTF_KEYUP}))
+	for i := len(modifiers) - 1; i >= 0; i-- {
+		inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{Vk: modifiers[i], Flags: w32.KEYEVENTF_KEYUP}))
+	}
+
+	w32.SendInput(inputs...)
+}
@@ -0,0 +1,16 @@
+//+build android
+
+package gowebview
+
+/*
+#include <jni.h>
+*/
+import "C"
+
+// Java_com_inkeliz_gowebview_gowebview_1android_webview_1drain implements the `webview_drain` of
+// gowebview_android.java, which runs the functions of Dispatch on the Android UI thread.
+//
+//export Java_com_inkeliz_gowebview_gowebview_1android_webview_1drain
+func Java_com_inkeliz_gowebview_gowebview_1android_webview_1drain(env *C.JNIEnv, cls C.jclass, handle C.jlong) {
+	drain(int64(handle))
+}
@@ -0,0 +1,156 @@
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/menu"
+)
+
+// Menu is the list of items of the menu bar or of the context menu, see WebView.SetMenu and WebView.SetContextMenu.
+type Menu struct {
+	Items []*MenuItem
+}
+
+// MenuItem is one item of the Menu.
+type MenuItem struct {
+	// Label is the text of the item. The roles have a default label. On Windows, the "&" marks the next letter as the
+	// mnemonic, use "&&" to display the ampersand.
+	Label string
+
+	// Accelerator is the keyboard shortcut, such as "Ctrl+Shift+S". The modifiers are Ctrl, Alt, Shift and Super,
+	// and the key is a letter, a digit, F1 to F24 or a name such as "Enter", "Plus" or "Delete". The roles have a
+	// default accelerator.
+	Accelerator string
+
+	// Role is the predefined action of the item, which is performed before the OnClick is called.
+	Role MenuRole
+
+	// Checkbox displays the check mark when Checked is true. The Checked is toggled when the item is clicked.
+	Checkbox bool
+	Checked  bool
+
+	// Disabled items are displayed, but can't be clicked.
+	Disabled bool
+
+	// Separator displays a line, it must not have Label, Accelerator or Submenu.
+	Separator bool
+
+	// Submenu are the items displayed when the item is selected.
+	Submenu []*MenuItem
+
+	// OnClick is called from the UI thread when the item is clicked or the Accelerator is pressed.
+	OnClick func(item *MenuItem)
+}
+
+// MenuRole is the predefined action of the MenuItem.
+type MenuRole int
+
+const (
+	// RoleNone has no action, only the OnClick is called.
+	RoleNone MenuRole = iota
+	RoleUndo
+	RoleRedo
+	RoleCut
+	RoleCopy
+	RolePaste
+	RoleSelectAll
+	RoleReload
+	RoleZoomIn
+	RoleZoomOut
+	RoleZoomReset
+	RoleFullscreen
+	RoleDevTools
+	RoleClose
+)
+
+// ContextMenuMode defines how the context menu of the page is changed, see WebView.SetContextMenu.
+type ContextMenuMode int
+
+const (
+	// ContextMenuDefault displays the default context menu of the browser, the Menu is ignored.
+	ContextMenuDefault ContextMenuMode = iota
+
+	// ContextMenuReplace displays only the items of the Menu.
+	ContextMenuReplace
+
+	// ContextMenuExtend appends the items of the Menu to the default context menu of the browser. On older runtimes,
+	// which can't change the default context menu, it displays the edit items (undo, redo, cut, copy, paste and select
+	// all) followed by the items of the Menu.
+	ContextMenuExtend
+)
+
+// messageContextMenu is posted by the contextMenuScript when the page doesn't handle the contextmenu event.
+const messageContextMenu = "gowebview:contextmenu"
+
+// contextMenuScript notifies the host about the right-click, after the listeners of the page, so the pages with their
+// own context menu are respected. The listener captures the event, since the page may stop its propagation, and the
+// check is deferred until all listeners run.
+const contextMenuScript = `(function () {
+	window.addEventListener('contextmenu', function (e) {
+		setTimeout(function () {
+			if (!e.defaultPrevented) {
+				window.chrome.webview.postMessage('` + messageContextMenu + `');
+			}
+		}, 0);
+	}, true);
+})();`
+
+// newMenuTree converts the Menu into the menu.Tree, the nil Menu returns a nil Tree. If extend is true, the edit items
+// are added before the items of the Menu.
+func newMenuTree(m *Menu, extend bool) (*menu.Tree, error) {
+	if m == nil && !extend {
+		return nil, nil
+	}
+
+	var items []*menu.Item
+	if m != nil {
+		items = newMenuItems(m.Items)
+	}
+	if extend {
+		items = menu.WithEdit(items)
+	}
+
+	return menu.New(items)
+}
+
+func newMenuItems(items []*MenuItem) []*menu.Item {
+	r := make([]*menu.Item, len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+
+		r[i] = &menu.Item{
+			Label:    item.Label,
+			Role:     menu.Role(item.Role),
+			Shortcut: item.Accelerator,
+			Checked:  item.Checked,
+			Disabled: item.Disabled,
+			Children: newMenuItems(item.Submenu),
+			Source:   item,
+		}
+
+		switch {
+		case item.Separator:
+			r[i].Kind = menu.Separator
+		case len(item.Submenu) > 0:
+			r[i].Kind = menu.Submenu
+		case item.Checkbox:
+			r[i].Kind = menu.Checkbox
+		}
+	}
+	return r
+}
+
+// menuClicked toggles the checkbox and calls the OnClick of the item. The role must be performed before.
+func menuClicked(item *menu.Item) {
+	source, _ := item.Source.(*MenuItem)
+	if item.Kind == menu.Checkbox {
+		item.Checked = !item.Checked
+		if source != nil {
+			source.Checked = item.Checked
+		}
+	}
+
+	if source != nil && source.OnClick != nil {
+		source.OnClick(source)
+	}
+}
@@ -0,0 +1,376 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/accel"
+	"github.com/inkeliz/gowebview/internal/menu"
+	"github.com/inkeliz/gowebview/internal/wincom"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	tpmRightButton = 0x0002
+	tpmReturnCmd   = 0x0100
+)
+
+// zoomStep is the factor used by RoleZoomIn and RoleZoomOut, the same of the browser.
+const zoomStep = 1.1
+
+func (w *webview) SetMenu(m *Menu) error {
+	tree, err := newMenuTree(m, false)
+	if err != nil {
+		return err
+	}
+
+	if w.view.parent != 0 {
+		return ErrFeatureNotSupported
+	}
+
+	w.thread.dispatch(func() {
+		w.setMenu(tree)
+	})
+	return nil
+}
+
+// setMenu replaces the menu bar. The frameless windows don't display the menu bar, but the accelerators still work. It
+// must be called from the UI thread.
+func (w *webview) setMenu(tree *menu.Tree) {
+	if w.view.window == 0 {
+		return
+	}
+
+	w.view.menu = tree
+	if w.config.WindowConfig.Style.Has(StyleFrameless) {
+		return
+	}
+
+	old := w.view.hmenu
+	w.view.hmenu = 0
+	if tree != nil {
+		w.view.hmenu = w32.CreateMenu()
+		appendMenu(w.view.hmenu, tree.Items)
+	}
+
+	w32.SetMenu(w.view.window, w.view.hmenu)
+	if old != 0 {
+		w32.DestroyMenu(old)
+	}
+	w32.DrawMenuBar(w.view.window)
+}
+
+func (w *webview) SetContextMenu(m *Menu, mode ContextMenuMode) error {
+	tree, err := newMenuTree(m, mode == ContextMenuExtend)
+	if err != nil {
+		return err
+	}
+
+	// The items are appended to the context menu of the browser, which already has the edit items.
+	var items *menu.Tree
+	if mode == ContextMenuExtend {
+		if items, err = newMenuTree(m, false); err != nil {
+			return err
+		}
+	}
+
+	if mode == ContextMenuDefault {
+		tree = nil
+	}
+
+	w.thread.dispatch(func() {
+		w.view.contextMenu = tree
+		w.view.contextMenuExtend = mode == ContextMenuExtend
+		w.view.contextMenuItems = items
+		w.applyContextMenu()
+	})
+	return nil
+}
+
+// nativeContextMenu returns true if the ContextMenuExtend appends the items to the context menu of the browser,
+// instead of displaying the custom context menu. It must be called from the UI thread.
+func (w *webview) nativeContextMenu() bool {
+	return w.view.contextMenuExtend && w.browser.contextMenuRequested
+}
+
+// addContextMenuRequested handles the ContextMenuRequested of the browser, see extendContextMenu. It isn't available
+// on older runtimes, which display the custom context menu instead. It must be called from the UI thread.
+func (w *webview) addContextMenuRequested() {
+	var webview11 *wincom.ICoreWebView2_11
+	res, _, _ := syscall.Syscall(w.browser.webview.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2_11)), uintptr(unsafe.Pointer(&webview11)))
+	if res != 0 || webview11 == nil {
+		return
+	}
+	defer syscall.Syscall(webview11.VTBL.Release, 1, uintptr(unsafe.Pointer(webview11)), 0, 0)
+
+	var environment9 *wincom.ICoreWebView2Environment9
+	res, _, _ = syscall.Syscall(w.browser.environment.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(w.browser.environment)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2Environment9)), uintptr(unsafe.Pointer(&environment9)))
+	if res != 0 || environment9 == nil {
+		return
+	}
+	syscall.Syscall(environment9.VTBL.Release, 1, uintptr(unsafe.Pointer(environment9)), 0, 0)
+
+	// The same handler is used by all items, it's kept alive until the browser is destroyed.
+	selected := &customItemSelectedHandler{webview: w}
+	selected.VTBL = customItemSelectedHandlerVTBL
+	handlers.Store(selected, true)
+	w.browser.events = append(w.browser.events, func() {
+		handlers.Delete(selected)
+	})
+
+	requested := &contextMenuRequestedHandler{webview: w}
+	requested.VTBL = contextMenuRequestedHandlerVTBL
+	w.addEvent(unsafe.Pointer(webview11), webview11.VTBL.AddContextMenuRequested, webview11.VTBL.RemoveContextMenuRequested, unsafe.Pointer(requested))
+
+	w.browser.contextMenuRequested = true
+	w.browser.customItemSelected = selected
+}
+
+// extendContextMenu appends the items of the ContextMenuExtend, after a separator, into the context menu of the
+// browser. It must be called from the UI thread.
+func (w *webview) extendContextMenu(args *wincom.ICoreWebView2ContextMenuRequestedEventArgs) {
+	tree := w.view.contextMenuItems
+	if !w.nativeContextMenu() || tree == nil {
+		return
+	}
+
+	var environment9 *wincom.ICoreWebView2Environment9
+	res, _, _ := syscall.Syscall(w.browser.environment.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(w.browser.environment)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2Environment9)), uintptr(unsafe.Pointer(&environment9)))
+	if res != 0 || environment9 == nil {
+		return
+	}
+	defer syscall.Syscall(environment9.VTBL.Release, 1, uintptr(unsafe.Pointer(environment9)), 0, 0)
+
+	var collection *wincom.ICoreWebView2ContextMenuItemCollection
+	if res, _, _ := syscall.Syscall(args.VTBL.GetMenuItems, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&collection)), 0); res != 0 || collection == nil {
+		return
+	}
+	defer syscall.Syscall(collection.VTBL.Release, 1, uintptr(unsafe.Pointer(collection)), 0, 0)
+
+	var count uint32
+	syscall.Syscall(collection.VTBL.GetCount, 2, uintptr(unsafe.Pointer(collection)), uintptr(unsafe.Pointer(&count)), 0)
+
+	w.view.contextMenuCommands = make(map[int32]*menu.Item)
+	w.insertContextMenuItems(environment9, collection, count, append([]*menu.Item{{Kind: menu.Separator}}, tree.Items...))
+}
+
+// insertContextMenuItems creates the items of the browser, and inserts them into the collection at the given index.
+// The submenus are inserted recursively. It must be called from the UI thread.
+func (w *webview) insertContextMenuItems(environment *wincom.ICoreWebView2Environment9, collection *wincom.ICoreWebView2ContextMenuItemCollection, index uint32, items []*menu.Item) {
+	for _, item := range items {
+		kind := uintptr(wincom.COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_COMMAND)
+		switch item.Kind {
+		case menu.Checkbox:
+			kind = wincom.COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_CHECK_BOX
+		case menu.Separator:
+			kind = wincom.COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_SEPARATOR
+		case menu.Submenu:
+			kind = wincom.COREWEBVIEW2_CONTEXT_MENU_ITEM_KIND_SUBMENU
+		}
+
+		var native *wincom.ICoreWebView2ContextMenuItem
+		res, _, _ := syscall.Syscall6(environment.VTBL.CreateContextMenuItem, 5, uintptr(unsafe.Pointer(environment)), uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(item.Label))), 0, kind, uintptr(unsafe.Pointer(&native)), 0)
+		if res != 0 || native == nil {
+			continue
+		}
+
+		if item.Disabled {
+			syscall.Syscall(native.VTBL.PutIsEnabled, 2, uintptr(unsafe.Pointer(native)), 0, 0)
+		}
+
+		switch item.Kind {
+		case menu.Submenu:
+			var children *wincom.ICoreWebView2ContextMenuItemCollection
+			if res, _, _ := syscall.Syscall(native.VTBL.GetChildren, 2, uintptr(unsafe.Pointer(native)), uintptr(unsafe.Pointer(&children)), 0); res == 0 && children != nil {
+				w.insertContextMenuItems(environment, children, 0, item.Children)
+				syscall.Syscall(children.VTBL.Release, 1, uintptr(unsafe.Pointer(children)), 0, 0)
+			}
+		case menu.Normal, menu.Checkbox:
+			if item.Checked {
+				syscall.Syscall(native.VTBL.PutIsChecked, 2, uintptr(unsafe.Pointer(native)), 1, 0)
+			}
+
+			var id int32
+			syscall.Syscall(native.VTBL.GetCommandId, 2, uintptr(unsafe.Pointer(native)), uintptr(unsafe.Pointer(&id)), 0)
+			w.view.contextMenuCommands[id] = item
+
+			var token wincom.EventRegistrationToken
+			syscall.Syscall(native.VTBL.AddCustomItemSelected, 3, uintptr(unsafe.Pointer(native)), uintptr(unsafe.Pointer(w.browser.customItemSelected)), uintptr(unsafe.Pointer(&token)))
+		}
+
+		syscall.Syscall(collection.VTBL.InsertValueAtIndex, 3, uintptr(unsafe.Pointer(collection)), uintptr(index), uintptr(unsafe.Pointer(native)))
+		syscall.Syscall(native.VTBL.Release, 1, uintptr(unsafe.Pointer(native)), 0, 0)
+		index++
+	}
+}
+
+// applyContextMenu disables the default context menu of the browser when the custom one is used, the
+// nativeContextMenu keeps it. It must be called from the UI thread.
+func (w *webview) applyContextMenu() {
+	if w.browser.webview == nil {
+		return
+	}
+
+	var settings *wincom.ICoreWebView2Settings
+	if res, _, _ := syscall.Syscall(w.browser.webview.VTBL.GetSettings, 2, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(&settings)), 0); res != 0 || settings == nil {
+		return
+	}
+	defer syscall.Syscall(settings.VTBL.Release, 1, uintptr(unsafe.Pointer(settings)), 0, 0)
+
+	enabled := uintptr(1)
+	if w.view.contextMenu != nil && !w.nativeContextMenu() {
+		enabled = 0
+	}
+	syscall.Syscall(settings.VTBL.PutAreDefaultContextMenusEnabled, 2, uintptr(unsafe.Pointer(settings)), enabled, 0)
+}
+
+// showContextMenu displays the custom context menu at the position of the cursor, and waits until the user selects
+// an item or dismiss it. It must be called from the UI thread, but not from the handlers of the browser, since the
+// menu runs its own message loop.
+func (w *webview) showContextMenu() {
+	tree := w.view.contextMenu
+	if tree == nil || w.view.window == 0 {
+		return
+	}
+
+	hmenu := w32.CreatePopupMenu()
+	defer w32.DestroyMenu(hmenu)
+	appendMenu(hmenu, tree.Items)
+
+	x, y, ok := w32.GetCursorPos()
+	if !ok {
+		return
+	}
+
+	// The menu isn't dismissed when the user clicks outside of it, unless the window is in the foreground.
+	w32.SetForegroundWindow(w.view.window)
+	if id := w32.TrackPopupMenu(hmenu, tpmRightButton|tpmReturnCmd, x, y, w.view.window, nil); id != 0 {
+		w.menuItemClicked(tree.Find(id))
+	}
+}
+
+// appendMenu appends the items into the native menu, the submenus are appended recursively.
+func appendMenu(hmenu w32.HMENU, items []*menu.Item) {
+	for _, item := range items {
+		var flags uint = w32.MF_STRING
+		if item.Disabled {
+			flags |= w32.MF_GRAYED
+		}
+
+		switch item.Kind {
+		case menu.Separator:
+			w32.AppendMenu(hmenu, w32.MF_SEPARATOR, 0, "")
+		case menu.Submenu:
+			submenu := w32.CreatePopupMenu()
+			appendMenu(submenu, item.Children)
+			w32.AppendMenu(hmenu, flags|w32.MF_POPUP, uintptr(submenu), item.Label)
+		default:
+			if item.Checked {
+				flags |= w32.MF_CHECKED
+			}
+
+			// The text after the tab is displayed aligned to the right, it's only informative.
+			label := item.Label
+			if item.HasAccelerator() {
+				label += "\t" + item.Accelerator.String()
+			}
+			w32.AppendMenu(hmenu, flags, uintptr(item.ID), label)
+		}
+	}
+}
+
+// menuItemClicked performs the role of the item and calls the OnClick. It must be called from the UI thread.
+func (w *webview) menuItemClicked(item *menu.Item) {
+	if item == nil || item.Disabled || item.Kind == menu.Submenu || item.Kind == menu.Separator {
+		return
+	}
+
+	w.performRole(item)
+	menuClicked(item)
+
+	if item.Kind == menu.Checkbox && w.view.hmenu != 0 && w.view.menu.Find(item.ID) == item {
+		var check uint = w32.MF_UNCHECKED
+		if item.Checked {
+			check = w32.MF_CHECKED
+		}
+		w32.CheckMenuItem(w.view.hmenu, uint(item.ID), check)
+	}
+}
+
+// performRole performs the action of the role. The native roles send their accelerator to the page, which handles it
+// as if the user pressed the keys. It must be called from the UI thread.
+func (w *webview) performRole(item *menu.Item) {
+	if item.Role.Native() {
+		sendChord(item.Accelerator)
+		return
+	}
+
+	switch item.Role {
+	case menu.RoleReload:
+		if w.browser.webview != nil {
+			syscall.Syscall(w.browser.webview.VTBL.Reload, 1, uintptr(unsafe.Pointer(w.browser.webview)), 0, 0)
+		}
+	case menu.RoleZoomIn:
+		w.setZoom(w.zoom() * zoomStep)
+	case menu.RoleZoomOut:
+		w.setZoom(w.zoom() / zoomStep)
+	case menu.RoleZoomReset:
+		w.setZoom(1)
+	case menu.RoleFullscreen:
+		w.setFullscreen(!w.view.fullscreen)
+	case menu.RoleDevTools:
+		if w.browser.webview != nil {
+			syscall.Syscall(w.browser.webview.VTBL.OpenDevToolsWindow, 1, uintptr(unsafe.Pointer(w.browser.webview)), 0, 0)
+		}
+	case menu.RoleClose:
+		w.requestClose()
+	}
+}
+
+// acceleratorItem returns the item of the menu bar with the given accelerator, or nil. It must be called from the UI
+// thread.
+func (w *webview) acceleratorItem(c accel.Chord) *menu.Item {
+	item := w.view.menu.Lookup(c)
+	if item == nil && c.Key == "Plus" {
+		item = w.view.menu.Lookup(accel.Chord{Modifiers: c.Modifiers, Key: "Equal"})
+	}
+	return item
+}
+
+// contextMenuRequestedHandler implements ICoreWebView2ContextMenuRequestedEventHandler.
+type contextMenuRequestedHandler struct {
+	wincom.ICoreWebView2ContextMenuRequestedEventHandler
+	webview *webview
+}
+
+var contextMenuRequestedHandlerVTBL = &wincom.ICoreWebView2ContextMenuRequestedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *contextMenuRequestedHandler, _ *wincom.ICoreWebView2, args *wincom.ICoreWebView2ContextMenuRequestedEventArgs) uintptr {
+		h.webview.extendContextMenu(args)
+		return 0
+	}),
+}
+
+// customItemSelectedHandler implements ICoreWebView2CustomItemSelectedEventHandler.
+type customItemSelectedHandler struct {
+	wincom.ICoreWebView2CustomItemSelectedEventHandler
+	webview *webview
+}
+
+var customItemSelectedHandlerVTBL = &wincom.ICoreWebView2CustomItemSelectedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *customItemSelectedHandler, sender *wincom.ICoreWebView2ContextMenuItem, _ uintptr) uintptr {
+		var id int32
+		syscall.Syscall(sender.VTBL.GetCommandId, 2, uintptr(unsafe.Pointer(sender)), uintptr(unsafe.Pointer(&id)), 0)
+
+		if item := h.webview.view.contextMenuCommands[id]; item != nil {
+			// The roles may send keys to the page, after the menu is closed.
+			h.webview.thread.dispatch(func() {
+				h.webview.menuItemClicked(item)
+			})
+		}
+		return 0
+	}),
+}
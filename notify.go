@@ -0,0 +1,156 @@
+package gowebview
+
+import (
+	"image"
+	"strconv"
+)
+
+// Buttons are the buttons displayed by the MessageBox.
+type Buttons int
+
+const (
+	ButtonsOK Buttons = iota
+	ButtonsOKCancel
+	ButtonsYesNo
+	ButtonsYesNoCancel
+	ButtonsRetryCancel
+)
+
+// Button is the button clicked by the user, see MessageBox.
+type Button int
+
+const (
+	// ButtonNone is returned with the error, when the MessageBox can't be displayed.
+	ButtonNone Button = iota
+	ButtonOK
+
+	// ButtonCancel is also returned when the user closes the MessageBox, if it has the cancel button.
+	ButtonCancel
+	ButtonYes
+	ButtonNo
+	ButtonRetry
+)
+
+// Notification is the notification displayed by the operating system, see WebView.Notify.
+type Notification struct {
+	Title string
+	Body  string
+
+	// Icon is displayed with the notification, by default it's the icon of the application. It's ignored on
+	// Android.
+	Icon image.Image
+
+	// OnClick is called when the user clicks the notification. The function is called from the UI thread. It's
+	// ignored on Android, which opens the application.
+	OnClick func()
+
+	// OnClose is called when the notification is closed, by the user, by the timeout or by other notification
+	// which replaces it. The function is called from the UI thread. It's ignored on Android.
+	OnClose func()
+}
+
+const (
+	// messageNotification is posted by the notificationScript, followed by the notification as JSON.
+	messageNotification = "gowebview:notification:"
+
+	// messageNotificationClose is posted by the notificationScript, followed by the id of the notification.
+	messageNotificationClose = "gowebview:notification-close:"
+
+	// notificationCallback is the function of the notificationScript which receives the events from the host, see
+	// notificationEvent.
+	notificationCallback = "__gowebviewNotification"
+)
+
+// notificationScript replaces the Notifications API of the page, the notifications are posted to the host, which
+// displays them using the Notify. The user can't be asked, the permission is granted by the host, see
+// notificationPermission. The host sends the show, click, close and error events back, see notificationEvent.
+func notificationScript(allowed bool) string {
+	return `(function () {
+	var permission = 'denied';
+	var notifications = {};
+	var ids = new WeakMap();
+	var next = 0;
+
+	class Notification extends EventTarget {
+		constructor(title, options) {
+			super();
+			options = options || {};
+			this.title = String(title);
+			this.body = String(options.body || '');
+			this.tag = String(options.tag || '');
+			this.data = options.data === undefined ? null : options.data;
+			this.onshow = this.onclick = this.onclose = this.onerror = null;
+
+			var n = this;
+			setTimeout(function () {
+				if (permission !== 'granted') {
+					n.dispatchEvent(new Event('error'));
+					return;
+				}
+				var id = ++next;
+				ids.set(n, id);
+				notifications[id] = n;
+				window.chrome.webview.postMessage('` + messageNotification + `' + JSON.stringify({id: id, title: n.title, body: n.body}));
+			}, 0);
+		}
+
+		close() {
+			var id = ids.get(this);
+			if (id && notifications[id]) {
+				window.chrome.webview.postMessage('` + messageNotificationClose + `' + id);
+			}
+		}
+
+		dispatchEvent(event) {
+			var result = super.dispatchEvent(event);
+			var handler = this['on' + event.type];
+			if (typeof handler === 'function') {
+				handler.call(this, event);
+			}
+			return result;
+		}
+
+		static get permission() {
+			return permission;
+		}
+
+		static requestPermission(callback) {
+			if (callback) {
+				callback(permission);
+			}
+			return Promise.resolve(permission);
+		}
+	}
+
+	Object.defineProperty(window, 'Notification', {value: Notification, writable: true, configurable: true});
+	Object.defineProperty(window, '` + notificationCallback + `', {value: function (id, type) {
+		if (id === 0) {
+			permission = type;
+			return;
+		}
+		var n = notifications[id];
+		if (!n) {
+			return;
+		}
+		if (type === 'close' || type === 'error') {
+			delete notifications[id];
+		}
+		n.dispatchEvent(new Event(type));
+	}});
+})();
+` + notificationPermission(allowed)
+}
+
+// notificationPermission returns the script which updates the permission of the notificationScript.
+func notificationPermission(allowed bool) string {
+	if allowed {
+		return notificationCallback + "(0, 'granted');"
+	}
+	return notificationCallback + "(0, 'denied');"
+}
+
+// notificationEvent returns the script which dispatches the event to the notification of the page, which has the
+// given id.
+func notificationEvent(id int, event string) string {
+	return notificationCallback + "(" + strconv.Itoa(id) + ", '" + event + "');"
+}
@@ -0,0 +1,237 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/inkeliz/gowebview/internal/ico"
+	"github.com/inkeliz/w32"
+	"strconv"
+)
+
+const (
+	nifInfo = 0x10
+
+	niifUser      = 0x04
+	niifLargeIcon = 0x20
+
+	// The balloon messages are sent as the lParam of the trayMessage.
+	ninBalloonHide      = w32.WM_USER + 3
+	ninBalloonTimeout   = w32.WM_USER + 4
+	ninBalloonUserClick = w32.WM_USER + 5
+)
+
+var messageBoxFlags = map[Buttons]uint{
+	ButtonsOK:          w32.MB_OK,
+	ButtonsOKCancel:    w32.MB_OKCANCEL,
+	ButtonsYesNo:       w32.MB_YESNO,
+	ButtonsYesNoCancel: w32.MB_YESNOCANCEL,
+	ButtonsRetryCancel: w32.MB_RETRYCANCEL,
+}
+
+var messageBoxButtons = map[int]Button{
+	w32.IDOK:     ButtonOK,
+	w32.IDCANCEL: ButtonCancel,
+	w32.IDYES:    ButtonYes,
+	w32.IDNO:     ButtonNo,
+	w32.IDRETRY:  ButtonRetry,
+}
+
+func (w *webview) MessageBox(title, text string, buttons Buttons) (button Button, err error) {
+	flags, ok := messageBoxFlags[buttons]
+	if !ok {
+		return ButtonNone, ErrInvalidButtons
+	}
+
+	err = w.thread.queue.DispatchSync(context.Background(), func() error {
+		if button = messageBoxButtons[w32.MessageBox(w.view.window, text, title, flags)]; button == ButtonNone {
+			return errors.New("MessageBox fails")
+		}
+		return nil
+	})
+	return button, err
+}
+
+func (w *webview) Notify(n Notification) error {
+	var data []byte
+	if n.Icon != nil {
+		b := new(bytes.Buffer)
+		if err := ico.Encode(b, n.Icon); err != nil {
+			return err
+		}
+		data = b.Bytes()
+	}
+
+	return w.thread.queue.DispatchSync(context.Background(), func() error {
+		tr, err := w.thread.notifier()
+		if err != nil {
+			return err
+		}
+
+		if !tr.balloon(n, data) {
+			tr.balloonClosed()
+			return errors.New("Shell_NotifyIcon fails")
+		}
+		return nil
+	})
+}
+
+// SetNotificationsAllowed grants or denies the Notifications API of the page.
+func (w *webview) SetNotificationsAllowed(allowed bool) {
+	w.thread.dispatch(func() {
+		if w.notifications == allowed {
+			return
+		}
+		w.notifications = allowed
+
+		// The script runs after the notificationScript, on every page, the eval updates the current page.
+		script := notificationPermission(allowed)
+		if w.browser.webview != nil {
+			w.addScript(script)
+			w.eval(script)
+		}
+	})
+}
+
+// webNotification displays the notification of the Notifications API, see notificationScript. The events are sent
+// back to the page, and the window is activated when the user clicks the notification. It must be called from the UI
+// thread.
+func (w *webview) webNotification(msg string) {
+	var n struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(msg), &n); err != nil || n.ID <= 0 {
+		return
+	}
+
+	if !w.notifications {
+		w.eval(notificationEvent(n.ID, "error"))
+		return
+	}
+
+	tr, err := w.thread.notifier()
+	if err != nil {
+		w.eval(notificationEvent(n.ID, "error"))
+		return
+	}
+
+	notification := Notification{
+		Title: n.Title,
+		Body:  n.Body,
+		OnClick: func() {
+			w.eval(notificationEvent(n.ID, "click"))
+			w.activate()
+		},
+		OnClose: func() {
+			if w.notification == n.ID {
+				w.notification, w.notificationTray = 0, nil
+			}
+			w.eval(notificationEvent(n.ID, "close"))
+		},
+	}
+
+	if !tr.balloon(notification, nil) {
+		tr.balloonClosed()
+		w.eval(notificationEvent(n.ID, "error"))
+		return
+	}
+
+	w.notification, w.notificationTray = n.ID, tr
+	w.eval(notificationEvent(n.ID, "show"))
+}
+
+// closeWebNotification hides the notification of the page, which has the given id, see notificationScript. It must
+// be called from the UI thread.
+func (w *webview) closeWebNotification(msg string) {
+	id, err := strconv.Atoi(msg)
+	if err != nil || id <= 0 || id != w.notification || w.notificationTray == nil {
+		return
+	}
+
+	w.notificationTray.hideBalloon()
+}
+
+// notifier returns the icon which displays the notifications. It's one of the Tray, if any, otherwise it's a
+// temporary icon. It must be called from the UI thread.
+func (t *thread) notifier() (*tray, error) {
+	var temporary *tray
+	for tr := range t.trays {
+		if !tr.temporary {
+			return tr, nil
+		}
+		temporary = tr
+	}
+	if temporary != nil {
+		return temporary, nil
+	}
+
+	tr := &tray{thread: t, temporary: true}
+	if err := tr.create(); err != nil {
+		return nil, err
+	}
+
+	tr.icon = trayIcon(nil)
+	if !tr.notify(nimAdd) {
+		tr.remove()
+		return nil, errors.New("Shell_NotifyIcon fails")
+	}
+
+	t.trays[tr] = true
+	return tr, nil
+}
+
+// balloon displays the notification, replacing the previous one. The icon is copied, so it's destroyed after that.
+// It must be called from the UI thread.
+func (tr *tray) balloon(n Notification, icon []byte) bool {
+	data := tr.data()
+	data.Flags |= nifInfo
+	copyString(data.InfoTitle[:], n.Title)
+	copyString(data.Info[:], n.Body)
+
+	if icon != nil {
+		if data.BalloonIcon = createIcon(icon, w32.GetSystemMetrics(w32.SM_CXICON)); data.BalloonIcon != 0 {
+			defer w32.DestroyIcon(data.BalloonIcon)
+			data.InfoFlags = niifUser | niifLargeIcon
+		}
+	}
+
+	if !shellNotify(nimModify, &data) {
+		return false
+	}
+
+	// The previous notification, if any, is replaced.
+	if f := tr.onBalloonClose; f != nil {
+		tr.onBalloonClose = nil
+		f()
+	}
+
+	tr.onBalloonClick, tr.onBalloonClose = n.OnClick, n.OnClose
+	return true
+}
+
+// hideBalloon removes the notification displayed by balloon. It must be called from the UI thread.
+func (tr *tray) hideBalloon() {
+	data := tr.data()
+	data.Flags |= nifInfo
+	shellNotify(nimModify, &data)
+	tr.balloonClosed()
+}
+
+// balloonClosed forgets the notification, and removes the temporary icon. The OnClose of the notification is
+// called. It must be called from the UI thread.
+func (tr *tray) balloonClosed() {
+	f := tr.onBalloonClose
+	tr.onBalloonClick, tr.onBalloonClose = nil, nil
+	if tr.temporary {
+		tr.remove()
+	}
+
+	if f != nil {
+		f()
+	}
+}
@@ -0,0 +1,52 @@
+package gowebview
+
+import (
+	"time"
+)
+
+// ProcessFailureKind is the kind of the failure of the browser process.
+type ProcessFailureKind int
+
+const (
+	// ProcessFailureBrowserExited is when the browser process exits, the WebView can't be used until it's recovered.
+	ProcessFailureBrowserExited ProcessFailureKind = iota
+
+	// ProcessFailureRenderExited is when the render process exits, the page is blank until it's reloaded.
+	ProcessFailureRenderExited
+
+	// ProcessFailureRenderUnresponsive is when the render process stops responding, the process is still running.
+	ProcessFailureRenderUnresponsive
+
+	// ProcessFailureRenderKilled is when the render process is killed by the system, such as to reclaim memory, the
+	// page is blank until it's reloaded. It's only reported on Android, the crashes are ProcessFailureRenderExited.
+	ProcessFailureRenderKilled
+)
+
+// ProcessFailure describes the failure of the browser process.
+type ProcessFailure struct {
+	Kind ProcessFailureKind
+
+	// ExitCode is the exit code of the process, or -1 if it's unknown or the process is still running.
+	ExitCode int
+
+	// Recovering is true if the Config.AutoRecover will try to recover the WebView.
+	Recovering bool
+}
+
+// RecoverPolicy defines how the WebView is recovered after the browser process fails. The browser is recreated and
+// the last URL is reloaded, the delay between attempts doubles on each consecutive failure. The zero values use the
+// defaults.
+type RecoverPolicy struct {
+	// MaxRetries is the maximum number of consecutive attempts, by default it's 3. Once exhausted, the RunContext
+	// returns ErrBrowserProcessFailed.
+	MaxRetries int
+
+	// InitialDelay is the delay before the first attempt, by default it's one second.
+	InitialDelay time.Duration
+
+	// MaxDelay is the maximum delay between attempts, by default it's 30 seconds.
+	MaxDelay time.Duration
+
+	// ResetAfter is the period without failures which restores the MaxRetries, by default it's one minute.
+	ResetAfter time.Duration
+}
@@ -0,0 +1,190 @@
+package gowebview
+
+import (
+	"context"
+	"github.com/inkeliz/gowebview/internal/migrate"
+	"github.com/inkeliz/gowebview/internal/profile"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Profile is the browser profile, which keeps the cookies, cache, storage and history. The same Profile can be used by
+// multiple WebViews at the same time.
+type Profile struct {
+	path      string
+	remove    func() error
+	ephemeral bool
+
+	mutex   sync.Mutex
+	views   int
+	idle    chan struct{}
+	removed bool
+
+	// wiped is closed once the ephemeral profile is removed, after the last WebView and App are destroyed.
+	wiping bool
+	wiped  chan struct{}
+}
+
+// NewProfile creates a Profile which stores the data into the given path. The directory is created by the browser
+// when the first WebView uses it.
+func NewProfile(path string) *Profile {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &Profile{path: path, idle: idle, remove: func() error { return os.RemoveAll(path) }}
+}
+
+// newEphemeralProfile creates a Profile on a new random directory, which is wiped and removed once all WebViews are
+// destroyed and the App, if any, is terminated.
+func newEphemeralProfile() (*Profile, error) {
+	root := filepath.Join(os.TempDir(), "gowebview")
+
+	// Removes the profiles left by previous executions, which didn't exit properly.
+	profile.Sweep(root)
+
+	e, err := profile.NewEphemeral(root)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewProfile(e.Path)
+	p.remove = e.Remove
+	p.ephemeral = true
+	p.wiped = make(chan struct{})
+
+	return p, nil
+}
+
+// Path returns the directory where the data of the profile is stored.
+func (p *Profile) Path() string {
+	return p.path
+}
+
+// Remove waits until all WebViews and Apps which uses the Profile are destroyed, then deletes the entire directory of the
+// profile. Once Remove is called, the Profile can't be used by new WebViews, New will return ErrProfileRemoved.
+//
+// It only returns nil if no data remains on disk. If the context is done before that, the error is returned.
+func (p *Profile) Remove(ctx context.Context) error {
+	p.mutex.Lock()
+	p.removed = true
+	idle := p.idle
+	p.mutex.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// The browser process might keep some files open for a while, even after the WebView is destroyed.
+	for {
+		err := p.remove()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// acquire marks the Profile as used by one WebView, or by the UI thread of the App. The returned function must be called when the WebView is
+// destroyed, it's safe to call it more than once.
+func (p *Profile) acquire() (release func(), err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.removed {
+		return nil, ErrProfileRemoved
+	}
+
+	if p.views == 0 {
+		p.idle = make(chan struct{})
+	}
+	p.views++
+
+	var once sync.Once
+	return func() { once.Do(p.release) }, nil
+}
+
+func (p *Profile) release() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.views--
+	if p.views > 0 {
+		return
+	}
+
+	close(p.idle)
+
+	if p.ephemeral {
+		p.removed = true
+		p.wiping = true
+
+		// The browser process keeps the files open until it exits, so it can't be removed while holding the mutex or
+		// the UI thread. See wait.
+		go func() {
+			// If it fails, the directory is removed by the next execution.
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			p.Remove(ctx)
+			close(p.wiped)
+		}()
+	}
+}
+
+// wait blocks until the ephemeral profile is removed, if it's no longer used by any WebView or App.
+func (p *Profile) wait() {
+	p.mutex.Lock()
+	wait := p.wiping && p.views == 0
+	p.mutex.Unlock()
+
+	if wait {
+		<-p.wiped
+	}
+}
+
+// MigrateUserData moves the browser data from one directory to another, such as from the previous default location
+// (`os.TempDir()/<Title>`) to the Config.UserDataDir. It does nothing if the old directory doesn't exist or if the new
+// directory already exists. The library (WebView2Loader.dll) is kept in the old directory, which is still the default
+// LibraryDir. It must be called before New.
+func MigrateUserData(from, to string) error {
+	return migrate.Move(from, to, libraryFiles)
+}
+
+// libraryFiles are the names of the files extracted into the LibraryDir, see extract.
+var libraryFiles = []string{"WebView2Loader.dll"}
+
+// DataKinds are used to select which browsing data must be cleared, multiple kinds can be combined, such as
+// DataCookies | DataCache.
+type DataKinds uint32
+
+const (
+	// DataCookies are the cookies of all websites
+	DataCookies DataKinds = 1 << iota
+
+	// DataCache is the HTTP disk cache
+	DataCache
+
+	// DataLocalStorage is the localStorage and sessionStorage
+	DataLocalStorage
+
+	// DataIndexedDB are the IndexedDB databases
+	DataIndexedDB
+
+	// DataServiceWorkers are the registered service workers and their caches
+	DataServiceWorkers
+
+	// DataHistory is the navigation history
+	DataHistory
+
+	// DataAll includes all kinds of data
+	DataAll = DataCookies | DataCache | DataLocalStorage | DataIndexedDB | DataServiceWorkers | DataHistory
+)
@@ -0,0 +1,199 @@
+package gowebview
+
+import (
+	"context"
+	"github.com/inkeliz/gowebview/internal/version"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Runtime is the status of the browser runtime, such as the WebView2 on Windows.
+type Runtime struct {
+	// Installed is true if the runtime is installed.
+	Installed bool
+
+	// Version is the version of the runtime, such as "86.0.616.0". It might be empty if the version is unknown.
+	Version string
+}
+
+// RuntimeStatus returns the status of the browser runtime, without creating any WebView.
+func RuntimeStatus() (Runtime, error) {
+	return runtimeStatus()
+}
+
+// BootstrapStage is the current step of the Bootstrap.
+type BootstrapStage int
+
+const (
+	// BootstrapChecking is when the installed runtime is being checked.
+	BootstrapChecking BootstrapStage = iota
+	// BootstrapDownloading is when the installer is being downloaded.
+	BootstrapDownloading
+	// BootstrapInstalling is when the installer is running.
+	BootstrapInstalling
+	// BootstrapCompleted is when the runtime is installed.
+	BootstrapCompleted
+)
+
+// BootstrapProgress is the progress reported by Bootstrap.
+type BootstrapProgress struct {
+	Stage BootstrapStage
+
+	// Current and Total are the number of bytes downloaded, on BootstrapDownloading. The Total might be -1 if the size
+	// is unknown. Both are zero on other stages.
+	Current, Total int64
+}
+
+// DefaultInstallerURL is the URL of the WebView2 Evergreen Bootstrapper, provided by Microsoft.
+const DefaultInstallerURL = "https://go.microsoft.com/fwlink/p/?LinkId=2124703"
+
+// BootstrapConfig defines how the runtime is installed.
+type BootstrapConfig struct {
+	// Installer is the path of the installer, such as the bundled MicrosoftEdgeWebview2Setup.exe or the standalone
+	// installer. If empty, the installer is downloaded from the InstallerURL.
+	Installer string
+
+	// InstallerURL is the URL of the installer, which is used when the Installer isn't defined. If empty, it's
+	// DefaultInstallerURL.
+	InstallerURL string
+
+	// MinimumVersion is the minimum version of the runtime, such as "86.0.616.0". If the installed runtime is older,
+	// the installer runs. If empty, or if the installed version is unknown, any version is accepted. It returns
+	// ErrInvalidVersion if it can't be parsed.
+	MinimumVersion string
+
+	// Progress is called on each stage and while downloading the installer. It might be nil.
+	Progress func(progress BootstrapProgress)
+}
+
+// Bootstrap installs the browser runtime if it's missing or older than the BootstrapConfig.MinimumVersion. It blocks
+// until the installer exits or the context is done. The config might be nil. On Android, it returns immediately without
+// error, since the Android System WebView is part of the system and its version is unknown, so the MinimumVersion is
+// only validated.
+func Bootstrap(ctx context.Context, config *BootstrapConfig) (Runtime, error) {
+	if config == nil {
+		config = new(BootstrapConfig)
+	}
+
+	progress := func(p BootstrapProgress) {
+		if config.Progress != nil {
+			config.Progress(p)
+		}
+	}
+
+	// The installed version is irrelevant, it only validates the minimum.
+	if err := version.Check("", config.MinimumVersion); err != nil {
+		return Runtime{}, err
+	}
+
+	progress(BootstrapProgress{Stage: BootstrapChecking})
+
+	status, err := runtimeStatus()
+	if err != nil {
+		return status, err
+	}
+
+	if status.Installed && version.Check(status.Version, config.MinimumVersion) == nil {
+		progress(BootstrapProgress{Stage: BootstrapCompleted})
+		return status, nil
+	}
+
+	installer := config.Installer
+	if installer == "" {
+		url := config.InstallerURL
+		if url == "" {
+			url = DefaultInstallerURL
+		}
+
+		dir, err := ioutil.TempDir("", "gowebview-installer")
+		if err != nil {
+			return status, err
+		}
+		defer os.RemoveAll(dir)
+
+		installer = filepath.Join(dir, "MicrosoftEdgeWebview2Setup.exe")
+		if err := download(ctx, url, installer, progress); err != nil {
+			return status, err
+		}
+	}
+
+	progress(BootstrapProgress{Stage: BootstrapInstalling})
+
+	if err := installRuntime(ctx, installer); err != nil {
+		return status, err
+	}
+
+	status, err = runtimeStatus()
+	if err != nil {
+		return status, err
+	}
+
+	if !status.Installed || version.Check(status.Version, config.MinimumVersion) != nil {
+		return status, ErrRuntimeNotInstalled
+	}
+
+	progress(BootstrapProgress{Stage: BootstrapCompleted})
+	return status, nil
+}
+
+// download saves the content of the URL into the given path, reporting the progress.
+func download(ctx context.Context, url, path string, progress func(p BootstrapProgress)) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &kindError{kind: ErrRuntimeNotInstalled, err: &downloadError{url: url, status: resp.StatusCode}}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0700)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	p := BootstrapProgress{Stage: BootstrapDownloading, Total: resp.ContentLength}
+	progress(p)
+
+	b := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(b)
+		if n > 0 {
+			if _, err := file.Write(b[:n]); err != nil {
+				return err
+			}
+
+			p.Current += int64(n)
+			progress(p)
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Close()
+}
+
+type downloadError struct {
+	url    string
+	status int
+}
+
+func (e *downloadError) Error() string {
+	return "download of " + e.url + " fails with status " + strconv.Itoa(e.status)
+}
@@ -0,0 +1,17 @@
+//+build android
+
+package gowebview
+
+import (
+	"context"
+)
+
+// runtimeStatus reports the Android System WebView as installed, it's part of the system and updated by the Play
+// Store. The version isn't available without the JVM.
+func runtimeStatus() (Runtime, error) {
+	return Runtime{Installed: true}, nil
+}
+
+func installRuntime(ctx context.Context, installer string) error {
+	return ErrFeatureNotSupported
+}
@@ -0,0 +1,112 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"context"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"unsafe"
+)
+
+// runtimeClient is the client ID of the WebView2 Runtime, used by EdgeUpdate.
+const runtimeClient = `Microsoft\EdgeUpdate\Clients\{F3017226-FE2A-4295-8BDF-00C3A9A7E4C5}`
+
+// runtimeStatus reads the version of the WebView2 Runtime from the registry, it's installed per-machine or per-user.
+func runtimeStatus() (Runtime, error) {
+	keys := []struct {
+		root   registry.Key
+		path   string
+		access uint32
+	}{
+		{root: registry.LOCAL_MACHINE, path: `SOFTWARE\` + runtimeClient, access: registry.WOW64_32KEY},
+		{root: registry.CURRENT_USER, path: `Software\` + runtimeClient},
+	}
+
+	for _, k := range keys {
+		key, err := registry.OpenKey(k.root, k.path, registry.QUERY_VALUE|k.access)
+		if err != nil {
+			continue
+		}
+
+		v, _, err := key.GetStringValue("pv")
+		key.Close()
+
+		// The version is "0.0.0.0" when the runtime is uninstalled, but the key remains.
+		if err != nil || v == "" || v == "0.0.0.0" {
+			continue
+		}
+
+		return Runtime{Installed: true, Version: v}, nil
+	}
+
+	return Runtime{}, nil
+}
+
+// installRuntime runs the installer silently, it needs administrator privileges to install per-machine.
+func installRuntime(ctx context.Context, installer string) error {
+	cmd := exec.CommandContext(ctx, installer, "/silent", "/install")
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &kindError{kind: ErrRuntimeNotInstalled, err: err}
+	}
+	return nil
+}
+
+// availableVersion returns the version of the WebView2 Runtime using the WebView2Loader.dll, which is also aware of
+// the non-stable channels. It returns ErrRuntimeNotInstalled if the runtime is missing, which avoids the
+// CreateCoreWebView2EnvironmentWithOptions to hang on some machines.
+func availableVersion(dll *windows.DLL, folder string) (string, error) {
+	proc, err := dll.FindProc("GetAvailableCoreWebView2BrowserVersionString")
+	if err != nil {
+		return "", &kindError{kind: ErrLibraryExtraction, err: err}
+	}
+
+	var folderPtr uintptr
+	if folder != "" {
+		folderPtr = uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(folder)))
+	}
+
+	var v *uint16
+	res, _, _ := proc.Call(folderPtr, uintptr(unsafe.Pointer(&v)))
+	if v != nil {
+		defer windows.CoTaskMemFree(unsafe.Pointer(v))
+	}
+
+	if res != 0 {
+		err := hresult("GetAvailableCoreWebView2BrowserVersionString", res)
+		if res == uintptr(0x80070002) {
+			return "", &kindError{kind: ErrRuntimeNotInstalled, err: err}
+		}
+		return "", err
+	}
+
+	if v == nil {
+		return "", ErrRuntimeNotInstalled
+	}
+
+	return windows.UTF16PtrToString(v), nil
+}
+
+// validateBrowserExecutableDir checks if the directory contains the fixed-version runtime.
+func validateBrowserExecutableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return &kindError{kind: ErrRuntimeNotInstalled, err: err}
+	}
+
+	if !info.IsDir() {
+		return &kindError{kind: ErrRuntimeNotInstalled, err: &os.PathError{Op: "open", Path: dir, Err: windows.ERROR_DIRECTORY}}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "msedgewebview2.exe")); err != nil {
+		return &kindError{kind: ErrRuntimeNotInstalled, err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+var (
+	shcore                 = windows.NewLazySystemDLL("shcore.dll")
+	getDpiForMonitor       = shcore.NewProc("GetDpiForMonitor")
+	setProcessDpiAwareness = shcore.NewProc("SetProcessDpiAwareness")
+
+	getDpiForWindow               = user32.NewProc("GetDpiForWindow")
+	setProcessDpiAwarenessContext = user32.NewProc("SetProcessDpiAwarenessContext")
+	setProcessDPIAware            = user32.NewProc("SetProcessDPIAware")
+
+	dpiAwareness sync.Once
+)
+
+// wmDpiChanged is the WM_DPICHANGED message, sent when the window moves to a monitor with a different DPI.
+const wmDpiChanged = 0x02E0
+
+// setDpiAwareness makes the process per-monitor DPI aware, so the windows aren't stretched (and blurry) by the
+// system. It uses the best mode available: per-monitor v2 (Windows 10 1703), per-monitor (Windows 8.1) or system
+// DPI aware. It does nothing if the awareness is already defined, such as by the manifest of the executable.
+func setDpiAwareness() {
+	dpiAwareness.Do(func() {
+		// DPI_AWARENESS_CONTEXT_PER_MONITOR_AWARE_V2 is ((DPI_AWARENESS_CONTEXT)-4).
+		if setProcessDpiAwarenessContext.Find() == nil {
+			if ok, _, _ := setProcessDpiAwarenessContext.Call(^uintptr(3)); ok != 0 {
+				return
+			}
+		}
+
+		// PROCESS_PER_MONITOR_DPI_AWARE is 2.
+		if setProcessDpiAwareness.Find() == nil {
+			if res, _, _ := setProcessDpiAwareness.Call(2); res == 0 {
+				return
+			}
+		}
+
+		if setProcessDPIAware.Find() == nil {
+			setProcessDPIAware.Call()
+		}
+	})
+}
+
+// windowScale returns the scale factor of the window, GetDpiForWindow is only available on Windows 10 1607 or newer.
+func windowScale(hwnd w32.HWND) float64 {
+	if getDpiForWindow.Find() == nil {
+		if dpi, _, _ := getDpiForWindow.Call(uintptr(hwnd)); dpi != 0 {
+			return float64(dpi) / 96
+		}
+	}
+
+	return monitorScale(w32.MonitorFromWindow(hwnd, w32.MONITOR_DEFAULTTONEAREST))
+}
+
+// screens enumerates all monitors, the primary monitor is the first one.
+func screens() []Screen {
+	// The callback receives the id, not the address of the list, since the Go stack might move during the call.
+	var list []Screen
+	id := atomic.AddUintptr(&enumerationID, 1)
+	enumerations.Store(id, &list)
+	w32.EnumDisplayMonitors(0, nil, enumMonitorsCallback, id)
+	enumerations.Delete(id)
+
+	for i := range list {
+		if list[i].Primary {
+			list[0], list[i] = list[i], list[0]
+			break
+		}
+	}
+
+	return list
+}
+
+// enumerations keeps the list of each call of screens, by its id, it's kinda of `map[uintptr]*[]Screen`.
+var (
+	enumerations  sync.Map
+	enumerationID uintptr
+)
+
+// enumMonitorsCallback is shared by all calls of EnumDisplayMonitors, since the callbacks created by
+// windows.NewCallback are never released.
+var enumMonitorsCallback = windows.NewCallback(func(monitor w32.HMONITOR, _ w32.HDC, _ *w32.RECT, id uintptr) uintptr {
+	v, ok := enumerations.Load(id)
+	if !ok {
+		return 0
+	}
+
+	list := v.(*[]Screen)
+	if s, ok := screenOf(monitor); ok {
+		*list = append(*list, s)
+	}
+	return 1
+})
+
+// screenOf returns the Screen of the given monitor.
+func screenOf(monitor w32.HMONITOR) (Screen, bool) {
+	info := w32.MONITORINFO{CbSize: uint32(unsafe.Sizeof(w32.MONITORINFO{}))}
+	if !w32.GetMonitorInfo(monitor, &info) {
+		return Screen{}, false
+	}
+
+	return Screen{
+		Bounds:   rectFrom(info.RcMonitor),
+		WorkArea: rectFrom(info.RcWork),
+		Scale:    monitorScale(monitor),
+		Primary:  info.DwFlags&w32.MONITORINFOF_PRIMARY != 0,
+	}, true
+}
+
+// monitorScale returns the scale factor of the monitor, GetDpiForMonitor is only available on Windows 8.1 or newer.
+func monitorScale(monitor w32.HMONITOR) float64 {
+	if getDpiForMonitor.Find() != nil {
+		return 1
+	}
+
+	// MDT_EFFECTIVE_DPI is 0.
+	var x, y uint32
+	if res, _, _ := getDpiForMonitor.Call(uintptr(monitor), 0, uintptr(unsafe.Pointer(&x)), uintptr(unsafe.Pointer(&y))); res != 0 || x == 0 {
+		return 1
+	}
+
+	return float64(x) / 96
+}
+
+func rectFrom(r w32.RECT) Rect {
+	return Rect{
+		X:      int64(r.Left),
+		Y:      int64(r.Top),
+		Width:  int64(r.Right - r.Left),
+		Height: int64(r.Bottom - r.Top),
+	}
+}
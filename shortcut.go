@@ -0,0 +1,38 @@
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/accel"
+)
+
+// BrowserAccelerators are the groups of keyboard shortcuts handled by the browser, see
+// WindowConfig.DisableAccelerators.
+type BrowserAccelerators int
+
+const (
+	// AcceleratorsReload are F5, Ctrl+R and their variants.
+	AcceleratorsReload = BrowserAccelerators(accel.Reload)
+
+	// AcceleratorsPrint are Ctrl+P and Ctrl+Shift+P.
+	AcceleratorsPrint = BrowserAccelerators(accel.Print)
+
+	// AcceleratorsFind are Ctrl+F, F3, Ctrl+G and their variants.
+	AcceleratorsFind = BrowserAccelerators(accel.Find)
+
+	// AcceleratorsZoom are Ctrl+Plus, Ctrl+Minus and Ctrl+0. The Ctrl+wheel and pinch are disabled by
+	// WindowConfig.DisableZoom.
+	AcceleratorsZoom = BrowserAccelerators(accel.Zoom)
+
+	// AcceleratorsDevTools are F12, Ctrl+Shift+I and their variants.
+	AcceleratorsDevTools = BrowserAccelerators(accel.DevTools)
+
+	// AcceleratorsAll disables all accelerators of the browser, including the ones not listed above, such as the
+	// navigation with Alt+Left, on newer runtimes. The keys used to edit the text, such as Ctrl+C, keep working.
+	AcceleratorsAll BrowserAccelerators = -1
+)
+
+// newShortcuts returns the Table which blocks the accelerators of the browser.
+func newShortcuts(disabled BrowserAccelerators) *accel.Table {
+	t := new(accel.Table)
+	t.Block(accel.Group(disabled))
+	return t
+}
@@ -0,0 +1,87 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/accel"
+	"github.com/inkeliz/gowebview/internal/wincom"
+	"golang.org/x/sys/windows"
+	"syscall"
+	"unsafe"
+)
+
+func (w *webview) RegisterShortcut(chord string, f func()) error {
+	c, err := accel.Parse(chord)
+	if err != nil {
+		return err
+	}
+
+	w.thread.dispatch(func() {
+		w.shortcuts.Register(c, f)
+	})
+	return nil
+}
+
+// disableAccelerators disables all accelerators of the browser, it's only available on newer runtimes. The blocked
+// accelerators of the shortcuts still work on older runtimes. It must be called from the UI thread.
+func (w *webview) disableAccelerators() {
+	var settings *wincom.ICoreWebView2Settings
+	if res, _, _ := syscall.Syscall(w.browser.webview.VTBL.GetSettings, 2, uintptr(unsafe.Pointer(w.browser.webview)), uintptr(unsafe.Pointer(&settings)), 0); res != 0 || settings == nil {
+		return
+	}
+	defer syscall.Syscall(settings.VTBL.Release, 1, uintptr(unsafe.Pointer(settings)), 0, 0)
+
+	var settings3 *wincom.ICoreWebView2Settings
+	res, _, _ := syscall.Syscall(settings.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(settings)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2Settings3)), uintptr(unsafe.Pointer(&settings3)))
+	if res == 0 && settings3 != nil {
+		syscall.Syscall(settings3.VTBL.PutAreBrowserAcceleratorKeysEnabled, 2, uintptr(unsafe.Pointer(settings3)), 0, 0)
+		syscall.Syscall(settings3.VTBL.Release, 1, uintptr(unsafe.Pointer(settings3)), 0, 0)
+	}
+}
+
+// acceleratorKeyPressedHandler implements ICoreWebView2AcceleratorKeyPressedEventHandler.
+type acceleratorKeyPressedHandler struct {
+	wincom.ICoreWebView2AcceleratorKeyPressedEventHandler
+	webview *webview
+}
+
+var acceleratorKeyPressedHandlerVTBL = &wincom.ICoreWebView2AcceleratorKeyPressedEventHandlerVTBL{
+	BasicVTBL: wincom.NewBasicVTBL(new(wincom.Basic)),
+	Invoke: windows.NewCallback(func(h *acceleratorKeyPressedHandler, _ *wincom.ICoreWebView2Controller, args *wincom.ICoreWebView2AcceleratorKeyPressedEventArgs) uintptr {
+		var kind, vk uint32
+		syscall.Syscall(args.VTBL.GetKeyEventKind, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&kind)), 0)
+		if kind != wincom.COREWEBVIEW2_KEY_EVENT_KIND_KEY_DOWN && kind != wincom.COREWEBVIEW2_KEY_EVENT_KIND_SYSTEM_KEY_DOWN {
+			return 0
+		}
+
+		syscall.Syscall(args.VTBL.GetVirtualKey, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&vk)), 0)
+		key := keyOf(uint16(vk))
+		if key == "" {
+			return 0
+		}
+
+		// The shortcuts take precedence over the menu, and the blocked accelerators are handled without any function.
+		c := accel.Chord{Modifiers: pressedModifiers(), Key: key}
+		f, handled := h.webview.shortcuts.Match(c)
+		if !handled {
+			if item := h.webview.acceleratorItem(c); item != nil {
+				f, handled = func() { h.webview.menuItemClicked(item) }, true
+			}
+		}
+		if !handled {
+			return 0
+		}
+
+		// The function is called once, even if the key is held down. The bit 30 of the lParam is the previous state.
+		var lParam int32
+		syscall.Syscall(args.VTBL.GetKeyEventLParam, 2, uintptr(unsafe.Pointer(args)), uintptr(unsafe.Pointer(&lParam)), 0)
+		syscall.Syscall(args.VTBL.PutHandled, 2, uintptr(unsafe.Pointer(args)), 1, 0)
+
+		if f != nil && lParam&(1<<30) == 0 {
+			// The browser must not be closed inside its own handler, such as by RoleClose, so the function is called
+			// later.
+			h.webview.thread.dispatch(f)
+		}
+		return 0
+	}),
+}
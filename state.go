@@ -0,0 +1,95 @@
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/winstate"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WindowState is the state of the window, saved by the StateStore.
+type WindowState struct {
+	// Bounds is the position and size of the window, in pixels, when it's not maximized.
+	Bounds Rect
+
+	// Visibility is VisibilityMaximized if the window was maximized, otherwise VisibilityDefault.
+	Visibility Visibility
+}
+
+// StateStore loads and saves the WindowState.
+type StateStore interface {
+	// Load returns the saved WindowState, or nil if there is none.
+	Load() (*WindowState, error)
+
+	// Save saves the WindowState, it's called when the window is closed.
+	Save(state *WindowState) error
+}
+
+// StateFile is the StateStore which keeps the WindowState as JSON in the file at the given path.
+type StateFile string
+
+// Load implements StateStore.
+func (f StateFile) Load() (*WindowState, error) {
+	b, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s, err := winstate.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &WindowState{Bounds: Rect(s.Bounds), Visibility: VisibilityDefault}
+	if s.Maximized {
+		state.Visibility = VisibilityMaximized
+	}
+
+	return state, nil
+}
+
+// Save implements StateStore. The file is replaced atomically, so it's never left half-written.
+func (f StateFile) Save(state *WindowState) error {
+	b, err := winstate.Encode(winstate.State{
+		Bounds:    winstate.Rect(state.Bounds),
+		Maximized: state.Visibility == VisibilityMaximized,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(f)), 0755); err != nil {
+		return err
+	}
+
+	tmp := string(f) + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, string(f))
+}
+
+// restoreState loads the WindowState from the store, the bounds are clamped to the work area of the given screens.
+// It returns nil if there is no valid state.
+func restoreState(store StateStore, screens []Screen) *WindowState {
+	if store == nil {
+		return nil
+	}
+
+	state, err := store.Load()
+	if err != nil || state == nil || state.Bounds.Width <= 0 || state.Bounds.Height <= 0 {
+		return nil
+	}
+
+	areas := make([]winstate.Rect, len(screens))
+	for i, s := range screens {
+		areas[i] = winstate.Rect(s.WorkArea)
+	}
+
+	state.Bounds = Rect(winstate.Clamp(winstate.Rect(state.Bounds), areas))
+	return state
+}
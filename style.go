@@ -0,0 +1,83 @@
+package gowebview
+
+// WindowStyle changes the appearance and behavior of the native window, multiple styles can be combined, such as
+// `StyleFrameless | StyleTransparent`. It's ignored on Android, which doesn't have windows. There's no Linux backend,
+// the GTK equivalents are pending, see the README.
+type WindowStyle int
+
+const (
+	// StyleFrameless removes the title bar and the borders of the window. The window is moved by dragging the
+	// elements with the `data-gowebview-drag` attribute or with the `-webkit-app-region: drag` CSS property, and
+	// double-clicking them maximizes the window. Use `data-gowebview-drag="false"` or `-webkit-app-region: no-drag` to
+	// exclude the nested elements, such as buttons.
+	StyleFrameless WindowStyle = 1 << iota
+
+	// StyleTransparent makes the background of the page transparent, the desktop is visible where the page doesn't
+	// draw a background. It's usually combined with StyleFrameless.
+	StyleTransparent
+
+	// StyleAlwaysOnTop keeps the window above all non-topmost windows, even when it's inactive.
+	StyleAlwaysOnTop
+
+	// StyleFixedSize prevents the user from resizing or maximizing the window. The SetSize and SetBounds still work.
+	StyleFixedSize
+
+	// StyleHiddenFromTaskbar hides the window from the taskbar and from the Alt+Tab switcher.
+	StyleHiddenFromTaskbar
+)
+
+// Has returns true if all the given styles are included.
+func (s WindowStyle) Has(style WindowStyle) bool {
+	return s&style == style
+}
+
+// Messages sent by the dragScript, they are prefixed to not conflict with the messages of the page.
+const (
+	messageDrag     = "gowebview:drag"
+	messageMaximize = "gowebview:maximize"
+)
+
+// dragScript sends messageDrag when the user presses the left button over a drag region, the double-click sends
+// messageMaximize. It's injected on every page when the window is StyleFrameless.
+const dragScript = `(function () {
+	function draggable(el) {
+		for (; el && el.nodeType === 1; el = el.parentElement) {
+			var attr = el.getAttribute('data-gowebview-drag');
+			if (attr !== null) {
+				return attr !== 'false';
+			}
+
+			var region = window.getComputedStyle(el).webkitAppRegion;
+			if (region === 'drag') {
+				return true;
+			}
+			if (region === 'no-drag') {
+				return false;
+			}
+		}
+		return false;
+	}
+
+	window.addEventListener('mousedown', function (e) {
+		if (e.button !== 0 || !draggable(e.target)) {
+			return;
+		}
+
+		e.preventDefault();
+		window.chrome.webview.postMessage(e.detail === 2 ? '` + messageMaximize + `' : '` + messageDrag + `');
+	}, true);
+})();`
+
+// Theme defines the colors of the title bar and the borders of the window.
+type Theme int
+
+const (
+	// ThemeSystem follows the preference of the user for the apps, it changes when the preference changes.
+	ThemeSystem Theme = iota
+
+	// ThemeLight uses the light title bar.
+	ThemeLight
+
+	// ThemeDark uses the dark title bar, it requires Windows 10 1809 or later.
+	ThemeDark
+)
@@ -0,0 +1,161 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"github.com/inkeliz/gowebview/internal/wincom"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	dwmapi                    = windows.NewLazySystemDLL("dwmapi.dll")
+	dwmEnableBlurBehindWindow = dwmapi.NewProc("DwmEnableBlurBehindWindow")
+	dwmSetWindowAttribute     = dwmapi.NewProc("DwmSetWindowAttribute")
+
+	isZoomed = user32.NewProc("IsZoomed")
+)
+
+// dwmBlurBehind is the DWM_BLURBEHIND, the w32.DWM_BLURBEHIND can't be used since the fields are unexported.
+type dwmBlurBehind struct {
+	flags      uint32
+	enable     int32
+	region     w32.HRGN
+	transition int32
+}
+
+// windowStyle returns the style and the extended style of the window, used by CreateWindowEx.
+func windowStyle(style WindowStyle) (ws uint, ex uint) {
+	ws = w32.WS_OVERLAPPEDWINDOW
+	if style.Has(StyleFixedSize) {
+		ws &^= w32.WS_THICKFRAME | w32.WS_MAXIMIZEBOX
+	}
+
+	if style.Has(StyleAlwaysOnTop) {
+		ex |= w32.WS_EX_TOPMOST
+	}
+
+	// The tool window isn't displayed on the taskbar and Alt+Tab, the title bar is smaller, but that doesn't matter
+	// when it's frameless.
+	if style.Has(StyleHiddenFromTaskbar) {
+		ex |= w32.WS_EX_TOOLWINDOW
+	}
+
+	return ws, ex
+}
+
+// framelessSize handles the WM_NCCALCSIZE of the StyleFrameless windows. It removes the title bar, but keeps the other
+// borders, which are invisible since Windows 10, so the window can still be resized and snapped.
+func framelessSize(hwnd w32.HWND, lParam uintptr) uintptr {
+	// The first field of NCCALCSIZE_PARAMS is the proposed rect of the window. The lParam is read as a pointer, instead
+	// of converting the uintptr, which the checkptr rules forbid.
+	r := *(**w32.RECT)(unsafe.Pointer(&lParam))
+	top, left := r.Top, r.Left
+
+	w32.DefWindowProc(hwnd, w32.WM_NCCALCSIZE, 1, lParam)
+
+	// The maximized window exceeds the screen by the size of the border, which is the same on all sides.
+	r.Top = top
+	if maximized, _, _ := isZoomed.Call(uintptr(hwnd)); maximized != 0 {
+		r.Top += r.Left - left
+	}
+
+	return 0
+}
+
+// transparent makes the background of the window transparent, using the blur behind of DWM with an empty region,
+// which enables the per-pixel alpha without blurring.
+func transparent(hwnd w32.HWND) {
+	region := w32.CreateRectRgn(0, 0, -1, -1)
+	defer w32.DeleteObject(w32.HGDIOBJ(region))
+
+	dwmEnableBlurBehindWindow.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&dwmBlurBehind{
+		flags:  w32.DWM_BB_ENABLE | w32.DWM_BB_BLURREGION,
+		enable: 1,
+		region: region,
+	})))
+}
+
+// applyStyle applies the WindowStyle to the browser, once it's created. It must be called from the UI thread.
+func (w *webview) applyStyle() {
+	style := w.config.WindowConfig.Style
+	if w.view.parent != 0 {
+		return
+	}
+
+	if style.Has(StyleTransparent) {
+		// The transparent background is only available on newer runtimes.
+		var controller2 *wincom.ICoreWebView2Controller2
+		res, _, _ := syscall.Syscall(w.browser.controller.VTBL.QueryInterface, 3, uintptr(unsafe.Pointer(w.browser.controller)), uintptr(unsafe.Pointer(&wincom.IID_ICoreWebView2Controller2)), uintptr(unsafe.Pointer(&controller2)))
+		if res == 0 && controller2 != nil {
+			// COREWEBVIEW2_COLOR is passed by value, zero is the transparent color.
+			syscall.Syscall(controller2.VTBL.PutDefaultBackgroundColor, 2, uintptr(unsafe.Pointer(controller2)), 0, 0)
+			syscall.Syscall(controller2.VTBL.Release, 1, uintptr(unsafe.Pointer(controller2)), 0, 0)
+		}
+	}
+
+	if style.Has(StyleFrameless) {
+		w.addScript(dragScript)
+	}
+}
+
+// drag starts moving the window, as if the user pressed the title bar. It must be called from the UI thread.
+func (w *webview) drag() {
+	if !w.config.WindowConfig.Style.Has(StyleFrameless) {
+		return
+	}
+
+	w32.ReleaseCapture()
+	w32.PostMessage(w.view.window, w32.WM_NCLBUTTONDOWN, w32.HTCAPTION, 0)
+}
+
+// toggleMaximize maximizes or restores the window, as if the user double-clicked the title bar. It must be called
+// from the UI thread.
+func (w *webview) toggleMaximize() {
+	style := w.config.WindowConfig.Style
+	if !style.Has(StyleFrameless) || style.Has(StyleFixedSize) {
+		return
+	}
+
+	if maximized, _, _ := isZoomed.Call(uintptr(w.view.window)); maximized != 0 {
+		w32.ShowWindow(w.view.window, w32.SW_RESTORE)
+		return
+	}
+
+	w32.ShowWindow(w.view.window, w32.SW_MAXIMIZE)
+}
+
+// applyTheme changes the colors of the title bar, according to the Theme. It must be called from the UI thread.
+func (w *webview) applyTheme() {
+	var dark int32
+	switch w.config.WindowConfig.Theme {
+	case ThemeDark:
+		dark = 1
+	case ThemeSystem:
+		if systemDark() {
+			dark = 1
+		}
+	}
+
+	// DWMWA_USE_IMMERSIVE_DARK_MODE is 20, but it's 19 before Windows 10 20H1. Older versions don't support it.
+	for _, attribute := range []uintptr{20, 19} {
+		if res, _, _ := dwmSetWindowAttribute.Call(uintptr(w.view.window), attribute, uintptr(unsafe.Pointer(&dark)), unsafe.Sizeof(dark)); res == 0 {
+			return
+		}
+	}
+}
+
+// systemDark returns true if the user prefers the dark theme for the apps.
+func systemDark() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+
+	light, _, err := k.GetIntegerValue("AppsUseLightTheme")
+	return err == nil && light == 0
+}
@@ -0,0 +1,21 @@
+package gowebview
+
+// ProgressState is the state of the progress displayed on the taskbar button, see WebView.SetProgress.
+type ProgressState int
+
+const (
+	// ProgressNone removes the progress.
+	ProgressNone ProgressState = iota
+
+	// ProgressIndeterminate displays an animation, the value is ignored.
+	ProgressIndeterminate
+
+	// ProgressNormal displays the value, usually in green.
+	ProgressNormal
+
+	// ProgressPaused displays the value, usually in yellow.
+	ProgressPaused
+
+	// ProgressError displays the value, usually in red.
+	ProgressError
+)
@@ -0,0 +1,197 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"bytes"
+	"github.com/inkeliz/gowebview/internal/ico"
+	"github.com/inkeliz/gowebview/internal/wincom"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"image"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	ole32            = windows.NewLazySystemDLL("ole32.dll")
+	coCreateInstance = ole32.NewProc("CoCreateInstance")
+
+	registerWindowMessage = user32.NewProc("RegisterWindowMessageW")
+
+	taskbarCreated     uint32
+	taskbarCreatedOnce sync.Once
+)
+
+// progressTotal is the total of the SetProgressValue, the value is converted to the fraction of it.
+const progressTotal = 10000
+
+// taskbarButtonCreated returns the message sent when the taskbar button is created, which also happens when the
+// explorer restarts. The ITaskbarList3 fails before that message.
+func taskbarButtonCreated() uint32 {
+	taskbarCreatedOnce.Do(func() {
+		msg, _, _ := registerWindowMessage.Call(uintptr(unsafe.Pointer(windows.StringToUTF16Ptr("TaskbarButtonCreated"))))
+		taskbarCreated = uint32(msg)
+	})
+	return taskbarCreated
+}
+
+// createIcon creates the icon of the given size from the ICO data. The icon must be destroyed with DestroyIcon.
+func createIcon(data []byte, size int) w32.HICON {
+	img, err := ico.Lookup(data, size)
+	if err != nil || len(img) == 0 {
+		return 0
+	}
+
+	// The 0x00030000 is the version of the icon format, required by CreateIconFromResourceEx.
+	return w32.CreateIconFromResourceEx(unsafe.Pointer(&img[0]), uint32(len(img)), true, 0x00030000, size, size, 0)
+}
+
+func (w *webview) SetIcon(icon image.Image) error {
+	var data []byte
+	if icon != nil {
+		b := new(bytes.Buffer)
+		if err := ico.Encode(b, icon); err != nil {
+			return err
+		}
+		data = b.Bytes()
+	}
+
+	w.thread.dispatch(func() {
+		w.view.iconData = data
+		w.updateIcon()
+	})
+	return nil
+}
+
+// updateIcon creates the icons for the scale of the current monitor, and replaces the previous ones. Without the
+// icon of SetIcon, the icons are removed, so the window uses the icon of the executable. It must be called from the
+// UI thread.
+func (w *webview) updateIcon() {
+	if w.view.window == 0 {
+		return
+	}
+
+	var small, big w32.HICON
+	if w.view.iconData != nil {
+		small, big = createIcon(w.view.iconData, w.physical(16)), createIcon(w.view.iconData, w.physical(32))
+	}
+	w32.SendMessage(w.view.window, w32.WM_SETICON, w32.ICON_SMALL, uintptr(small))
+	w32.SendMessage(w.view.window, w32.WM_SETICON, w32.ICON_BIG, uintptr(big))
+
+	w.destroyIcons()
+	w.view.smallIcon, w.view.bigIcon = small, big
+}
+
+// destroyIcons destroys the icons created by SetIcon, they must not be used by the window anymore. It must be called
+// from the UI thread.
+func (w *webview) destroyIcons() {
+	for _, icon := range []w32.HICON{w.view.smallIcon, w.view.bigIcon} {
+		if icon != 0 {
+			w32.DestroyIcon(icon)
+		}
+	}
+	w.view.smallIcon, w.view.bigIcon = 0, 0
+}
+
+func (w *webview) SetProgress(state ProgressState, value float64) {
+	w.thread.dispatch(func() {
+		w.view.progress, w.view.progressValue = state, value
+		w.updateTaskbar()
+	})
+}
+
+func (w *webview) SetBadge(badge image.Image, description string) error {
+	var data []byte
+	if badge != nil {
+		b := new(bytes.Buffer)
+		if err := ico.Encode(b, badge, 16, 20, 24, 32); err != nil {
+			return err
+		}
+		data = b.Bytes()
+	}
+
+	w.thread.dispatch(func() {
+		if w.view.window == 0 {
+			return
+		}
+
+		previous := w.view.badge
+		w.view.badge, w.view.badgeDescription = 0, description
+		if data != nil {
+			w.view.badge = createIcon(data, w.physical(16))
+		}
+
+		w.updateTaskbar()
+
+		if previous != 0 {
+			w32.DestroyIcon(previous)
+		}
+	})
+	return nil
+}
+
+// updateTaskbar displays the progress and the badge on the taskbar button. It's called again when the button is
+// created, since it might be called before that. It must be called from the UI thread.
+func (w *webview) updateTaskbar() {
+	list := w.thread.taskbarList()
+	if list == nil || w.view.window == 0 {
+		return
+	}
+
+	value := w.view.progressValue
+	if value < 0 {
+		value = 0
+	}
+	if value > 1 {
+		value = 1
+	}
+
+	// The SetProgressValue also changes the state, so it's called before the SetProgressState.
+	var flags uintptr
+	switch w.view.progress {
+	case ProgressIndeterminate:
+		flags = wincom.TBPF_INDETERMINATE
+	case ProgressNormal:
+		flags = wincom.TBPF_NORMAL
+	case ProgressPaused:
+		flags = wincom.TBPF_PAUSED
+	case ProgressError:
+		flags = wincom.TBPF_ERROR
+	}
+
+	if flags&(wincom.TBPF_NORMAL|wincom.TBPF_PAUSED|wincom.TBPF_ERROR) != 0 {
+		syscall.Syscall6(list.VTBL.SetProgressValue, 4, uintptr(unsafe.Pointer(list)), uintptr(w.view.window), uintptr(value*progressTotal), progressTotal, 0, 0)
+	}
+	syscall.Syscall(list.VTBL.SetProgressState, 3, uintptr(unsafe.Pointer(list)), uintptr(w.view.window), flags)
+
+	var description uintptr
+	if w.view.badgeDescription != "" {
+		description = uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(w.view.badgeDescription)))
+	}
+	syscall.Syscall6(list.VTBL.SetOverlayIcon, 4, uintptr(unsafe.Pointer(list)), uintptr(w.view.window), uintptr(w.view.badge), description, 0, 0)
+}
+
+// taskbarList returns the ITaskbarList3, shared by all windows of the thread. It returns nil if it isn't available.
+// It must be called from the UI thread.
+func (t *thread) taskbarList() *wincom.ITaskbarList3 {
+	if t.taskbar != nil {
+		return t.taskbar
+	}
+
+	// CLSCTX_INPROC_SERVER is 0x1.
+	var list *wincom.ITaskbarList3
+	res, _, _ := coCreateInstance.Call(uintptr(unsafe.Pointer(&wincom.CLSID_TaskbarList)), 0, 0x1, uintptr(unsafe.Pointer(&wincom.IID_ITaskbarList3)), uintptr(unsafe.Pointer(&list)))
+	if res != 0 || list == nil {
+		return nil
+	}
+
+	if res, _, _ := syscall.Syscall(list.VTBL.HrInit, 1, uintptr(unsafe.Pointer(list)), 0, 0); res != 0 {
+		syscall.Syscall(list.VTBL.Release, 1, uintptr(unsafe.Pointer(list)), 0, 0)
+		return nil
+	}
+
+	t.taskbar = list
+	return t.taskbar
+}
@@ -0,0 +1,48 @@
+package gowebview
+
+import (
+	"image"
+)
+
+// Tray is the icon in the notification area of the taskbar, also known as system tray, see App.NewTray. It is safe
+// to call the functions from a background thread. It's only implemented on Windows, the StatusNotifierItem of Linux
+// is pending, see the README.
+type Tray interface {
+	// SetIcon replaces the icon, which is resized to the size of the notification area. The nil icon restores the
+	// icon of the executable. It returns the error if the image can't be encoded.
+	SetIcon(icon image.Image) error
+
+	// SetTooltip replaces the text displayed when the cursor is over the icon.
+	SetTooltip(tooltip string)
+
+	// SetMenu replaces the menu displayed when the icon is right-clicked, the nil Menu removes it. The roles of the
+	// items aren't performed, since the menu doesn't belong to any window, only the OnClick is called.
+	SetMenu(menu *Menu) error
+
+	// OnClick adds a function which is called when the icon is clicked. The function is called from the UI thread.
+	OnClick(f func())
+
+	// OnDoubleClick adds a function which is called when the icon is double-clicked, after the OnClick. The
+	// function is called from the UI thread.
+	OnDoubleClick(f func())
+
+	// Remove removes the icon from the notification area, the Tray must not be used after that.
+	Remove()
+}
+
+// TrayConfig defines the initial state of the Tray.
+type TrayConfig struct {
+	// Icon is the icon of the Tray, by default it's the icon of the executable.
+	Icon image.Image
+
+	// Tooltip is the text displayed when the cursor is over the icon.
+	Tooltip string
+
+	// Menu is displayed when the icon is right-clicked, see Tray.SetMenu.
+	Menu *Menu
+
+	// Window is displayed when the icon is clicked, and hidden when the icon is clicked again. That is useful for
+	// background applications, which create the window with VisibilityHidden and use OnCloseRequested to hide
+	// the window instead of closing it.
+	Window WebView
+}
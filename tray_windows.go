@@ -0,0 +1,413 @@
+//+build windows,amd64
+
+package gowebview
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/inkeliz/gowebview/internal/ico"
+	"github.com/inkeliz/gowebview/internal/menu"
+	"github.com/inkeliz/w32"
+	"golang.org/x/sys/windows"
+	"image"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+var (
+	shell32         = windows.NewLazySystemDLL("shell32.dll")
+	shellNotifyIcon = shell32.NewProc("Shell_NotifyIconW")
+
+	isIconic = user32.NewProc("IsIconic")
+
+	taskbarRestartedMsg  uint32
+	taskbarRestartedOnce sync.Once
+
+	trayClassOnce sync.Once
+	trayClassErr  error
+)
+
+const (
+	nimAdd    = 0x0
+	nimModify = 0x1
+	nimDelete = 0x2
+
+	nifMessage = 0x01
+	nifIcon    = 0x02
+	nifTip     = 0x04
+)
+
+// trayMessage is the message sent by the notification area when the user interacts with the icon, the lParam is the
+// mouse message, such as WM_LBUTTONUP.
+const trayMessage = w32.WM_APP + 1
+
+// notifyIconData is the NOTIFYICONDATAW.
+type notifyIconData struct {
+	Size            uint32
+	Window          w32.HWND
+	ID              uint32
+	Flags           uint32
+	CallbackMessage uint32
+	Icon            w32.HICON
+	Tip             [128]uint16
+	State           uint32
+	StateMask       uint32
+	Info            [256]uint16
+	Version         uint32
+	InfoTitle       [64]uint16
+	InfoFlags       uint32
+	GUID            windows.GUID
+	BalloonIcon     w32.HICON
+}
+
+// copyString copies the string into the buffer, truncating it if needed. The buffer always ends with the NUL.
+func copyString(dst []uint16, s string) {
+	src, _ := windows.UTF16FromString(s)
+	if len(src) > len(dst) {
+		src = src[:len(dst)]
+		src[len(src)-1] = 0
+	}
+	copy(dst, src)
+}
+
+// taskbarRestarted returns the message broadcast when the explorer restarts, the icons must be added again.
+func taskbarRestarted() uint32 {
+	taskbarRestartedOnce.Do(func() {
+		msg, _, _ := registerWindowMessage.Call(uintptr(unsafe.Pointer(windows.StringToUTF16Ptr("TaskbarCreated"))))
+		taskbarRestartedMsg = uint32(msg)
+	})
+	return taskbarRestartedMsg
+}
+
+// traylist is kinda of `map[hwnd]*tray`.
+var traylist sync.Map
+
+type tray struct {
+	thread *thread
+	window w32.HWND
+
+	icon    w32.HICON
+	tooltip string
+	menu    *menu.Tree
+	target  *webview
+
+	onClick       []func()
+	onDoubleClick []func()
+	doubleClicked bool
+
+	// temporary is true if the icon was added by Notify, it's removed when the notification is closed.
+	temporary      bool
+	onBalloonClick func()
+	onBalloonClose func()
+}
+
+func (t *thread) newTray(config *TrayConfig) (Tray, error) {
+	tree, err := newMenuTree(config.Menu, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if config.Icon != nil {
+		b := new(bytes.Buffer)
+		if err := ico.Encode(b, config.Icon); err != nil {
+			return nil, err
+		}
+		data = b.Bytes()
+	}
+
+	tr := &tray{thread: t, tooltip: config.Tooltip, menu: tree}
+	if w, ok := config.Window.(*webview); ok {
+		tr.target = w
+	}
+
+	err = t.queue.DispatchSync(context.Background(), func() error {
+		if err := tr.create(); err != nil {
+			return err
+		}
+
+		tr.icon = trayIcon(data)
+		if !tr.notify(nimAdd) {
+			tr.remove()
+			return errors.New("Shell_NotifyIcon fails")
+		}
+
+		t.trays[tr] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tr, nil
+}
+
+// create creates the hidden window which receives the messages of the icon. The message-only windows can't be used,
+// since they don't receive the broadcast of taskbarRestarted. It must be called from the UI thread.
+func (tr *tray) create() error {
+	instance := w32.GetModuleHandle("")
+
+	trayClassOnce.Do(func() {
+		if _, ok := w32.GetClassInfoEx(instance, "webview-tray"); ok {
+			return
+		}
+
+		class := w32.RegisterClassEx(&w32.WNDCLASSEX{
+			WndProc:   windows.NewCallback(watchTray),
+			Instance:  instance,
+			ClassName: windows.StringToUTF16Ptr("webview-tray"),
+		})
+		if class == 0 {
+			trayClassErr = errors.New("RegisterClassEx fails")
+		}
+	})
+	if trayClassErr != nil {
+		return trayClassErr
+	}
+
+	tr.window = w32.CreateWindowEx(0, windows.StringToUTF16Ptr("webview-tray"), windows.StringToUTF16Ptr(""), 0, 0, 0, 0, 0, 0, 0, instance, nil)
+	if tr.window == 0 {
+		return errors.New("CreateWindowEx failed")
+	}
+
+	traylist.Store(tr.window, tr)
+	return nil
+}
+
+// notify adds, modifies or deletes the icon. It must be called from the UI thread.
+func (tr *tray) notify(action uintptr) bool {
+	data := tr.data()
+	return shellNotify(action, &data)
+}
+
+// data returns the NOTIFYICONDATAW of the icon, with the tooltip.
+func (tr *tray) data() notifyIconData {
+	data := notifyIconData{
+		Window:          tr.window,
+		ID:              1,
+		Flags:           nifMessage | nifIcon | nifTip,
+		CallbackMessage: trayMessage,
+		Icon:            tr.icon,
+	}
+	data.Size = uint32(unsafe.Sizeof(data))
+	copyString(data.Tip[:], tr.tooltip)
+	return data
+}
+
+func shellNotify(action uintptr, data *notifyIconData) bool {
+	ok, _, _ := shellNotifyIcon.Call(action, uintptr(unsafe.Pointer(data)))
+	return ok != 0
+}
+
+// trayIcon creates the icon from the ICO data, the nil data uses the icon of the executable. The icon must be
+// destroyed with DestroyIcon.
+func trayIcon(data []byte) w32.HICON {
+	if data == nil {
+		if path, err := os.Executable(); err == nil {
+			return w32.ExtractIcon(path, 0)
+		}
+		return 0
+	}
+
+	return createIcon(data, w32.GetSystemMetrics(w32.SM_CXSMICON))
+}
+
+func (tr *tray) SetIcon(icon image.Image) error {
+	var data []byte
+	if icon != nil {
+		b := new(bytes.Buffer)
+		if err := ico.Encode(b, icon); err != nil {
+			return err
+		}
+		data = b.Bytes()
+	}
+
+	tr.thread.dispatch(func() {
+		if tr.window == 0 {
+			return
+		}
+
+		// The icon is copied by the notification area, so the previous one is destroyed after that.
+		previous := tr.icon
+		tr.icon = trayIcon(data)
+		tr.notify(nimModify)
+
+		if previous != 0 {
+			w32.DestroyIcon(previous)
+		}
+	})
+	return nil
+}
+
+func (tr *tray) SetTooltip(tooltip string) {
+	tr.thread.dispatch(func() {
+		if tr.window == 0 {
+			return
+		}
+
+		tr.tooltip = tooltip
+		tr.notify(nimModify)
+	})
+}
+
+func (tr *tray) SetMenu(m *Menu) error {
+	tree, err := newMenuTree(m, false)
+	if err != nil {
+		return err
+	}
+
+	tr.thread.dispatch(func() {
+		tr.menu = tree
+	})
+	return nil
+}
+
+func (tr *tray) OnClick(f func()) {
+	tr.thread.dispatch(func() {
+		tr.onClick = append(tr.onClick, f)
+	})
+}
+
+func (tr *tray) OnDoubleClick(f func()) {
+	tr.thread.dispatch(func() {
+		tr.onDoubleClick = append(tr.onDoubleClick, f)
+	})
+}
+
+func (tr *tray) Remove() {
+	tr.thread.dispatch(tr.remove)
+}
+
+// remove deletes the icon and destroys the window. It must be called from the UI thread.
+func (tr *tray) remove() {
+	if tr.window == 0 {
+		return
+	}
+
+	tr.notify(nimDelete)
+
+	traylist.Delete(tr.window)
+	delete(tr.thread.trays, tr)
+	w32.DestroyWindow(tr.window)
+	tr.window = 0
+
+	if tr.icon != 0 {
+		w32.DestroyIcon(tr.icon)
+		tr.icon = 0
+	}
+}
+
+// click toggles the TrayConfig.Window and calls the OnClick. It must be called from the UI thread.
+func (tr *tray) click() {
+	if w := tr.target; w != nil {
+		w.thread.dispatch(w.toggleVisibility)
+	}
+
+	for _, f := range tr.onClick {
+		f()
+	}
+}
+
+// showMenu displays the menu at the position of the cursor, and waits until the user selects an item or dismiss it.
+// It must be called from the UI thread.
+func (tr *tray) showMenu() {
+	tree := tr.menu
+	if tree == nil {
+		return
+	}
+
+	hmenu := w32.CreatePopupMenu()
+	defer w32.DestroyMenu(hmenu)
+	appendMenu(hmenu, tree.Items)
+
+	x, y, ok := w32.GetCursorPos()
+	if !ok {
+		return
+	}
+
+	// The menu isn't dismissed when the user clicks outside of it, unless the window is in the foreground. The
+	// WM_NULL makes the menu work again when it's displayed for the second time.
+	w32.SetForegroundWindow(tr.window)
+	id := w32.TrackPopupMenu(hmenu, tpmRightButton|tpmReturnCmd, x, y, tr.window, nil)
+	w32.PostMessage(tr.window, w32.WM_NULL, 0, 0)
+
+	if item := tree.Find(id); item != nil && !item.Disabled && (item.Kind == menu.Normal || item.Kind == menu.Checkbox) {
+		menuClicked(item)
+	}
+}
+
+func watchTray(hwnd w32.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	tt, ok := traylist.Load(hwnd)
+	if !ok {
+		return w32.DefWindowProc(hwnd, msg, wParam, lParam)
+	}
+
+	tr := tt.(*tray)
+
+	switch {
+	case msg == trayMessage:
+		switch lParam & 0xFFFF {
+		case w32.WM_LBUTTONUP:
+			// The double-click is sent as WM_LBUTTONUP, WM_LBUTTONDBLCLK and WM_LBUTTONUP, the second click is
+			// part of the double-click.
+			if tr.doubleClicked {
+				tr.doubleClicked = false
+				break
+			}
+			tr.click()
+		case w32.WM_LBUTTONDBLCLK:
+			tr.doubleClicked = true
+			for _, f := range tr.onDoubleClick {
+				f()
+			}
+		case w32.WM_RBUTTONUP:
+			tr.showMenu()
+		case ninBalloonUserClick:
+			// The click is reported before the close, like the browser does.
+			if f := tr.onBalloonClick; f != nil {
+				tr.onBalloonClick = nil
+				f()
+			}
+			tr.balloonClosed()
+		case ninBalloonHide, ninBalloonTimeout:
+			tr.balloonClosed()
+		}
+		return 0
+	case msg == taskbarRestarted():
+		tr.notify(nimAdd)
+	}
+
+	return w32.DefWindowProc(hwnd, msg, wParam, lParam)
+}
+
+// toggleVisibility hides the window if it's visible, otherwise it displays and activates the window. It must be
+// called from the UI thread.
+func (w *webview) toggleVisibility() {
+	if w.view.window == 0 {
+		return
+	}
+
+	if iconic, _, _ := isIconic.Call(uintptr(w.view.window)); w32.IsWindowVisible(w.view.window) && iconic == 0 {
+		w.setVisibility(VisibilityHidden)
+		return
+	}
+
+	w.activate()
+}
+
+// activate displays the window, restoring it if minimized, and brings it to the foreground. It must be called from
+// the UI thread.
+func (w *webview) activate() {
+	if w.view.window == 0 {
+		return
+	}
+
+	if iconic, _, _ := isIconic.Call(uintptr(w.view.window)); iconic != 0 {
+		w32.ShowWindow(w.view.window, w32.SW_RESTORE)
+	} else {
+		w32.ShowWindow(w.view.window, w32.SW_SHOW)
+	}
+	w32.SetForegroundWindow(w.view.window)
+}